@@ -0,0 +1,156 @@
+// Command daytona-import-gen prints Terraform import blocks and skeleton
+// resources for the snapshots that already exist in a Daytona organization,
+// so they can be brought under management with `terraform plan
+// -generate-config-out` or by editing the output directly.
+//
+// Only snapshots are enumerated, the provider has no resources for sandboxes
+// or volumes yet.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/daytonaio/apiclient"
+)
+
+const pageSize = 100
+
+var invalidLabelCharacters = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+func main() {
+	var endpoint, organizationID, destroyBehavior string
+
+	defaultEndpoint := os.Getenv("DAYTONA_API_URL")
+	if defaultEndpoint == "" {
+		defaultEndpoint = "https://app.daytona.io/api"
+	}
+
+	flag.StringVar(&endpoint, "endpoint", defaultEndpoint, "the Daytona API endpoint, defaults to DAYTONA_API_URL")
+	flag.StringVar(&organizationID, "organization-id", os.Getenv("DAYTONA_ORGANIZATION_ID"), "the organization to enumerate, defaults to DAYTONA_ORGANIZATION_ID")
+	flag.StringVar(&destroyBehavior, "destroy-behavior", "retain", "the destroy_behavior of the generated resources, retain leaves the snapshots in Daytona when destroying them")
+	flag.Parse()
+
+	token := os.Getenv("DAYTONA_TOKEN")
+	if token == "" {
+		log.Fatal("DAYTONA_TOKEN must be set")
+	}
+	if organizationID == "" {
+		log.Fatal("-organization-id or DAYTONA_ORGANIZATION_ID must be set")
+	}
+	if !slices.Contains([]string{"delete", "deactivate", "retain"}, destroyBehavior) {
+		log.Fatal("-destroy-behavior must be delete, deactivate or retain")
+	}
+
+	cfg := apiclient.NewConfiguration()
+	cfg.Servers = []apiclient.ServerConfiguration{{
+		URL: endpoint,
+	}}
+	cfg.DefaultHeader = map[string]string{
+		"Authorization":             "Bearer " + token,
+		"X-Daytona-Organization-ID": organizationID,
+	}
+
+	snapshots, err := listSnapshots(context.Background(), apiclient.NewAPIClient(cfg))
+	if err != nil {
+		log.Fatalf("unable to list snapshots: %v", err)
+	}
+
+	writeSnapshots(os.Stdout, snapshots, destroyBehavior)
+}
+
+// listSnapshots fetches all snapshots owned by the organization, skipping
+// Daytona's general snapshots that are shared with everyone.
+func listSnapshots(ctx context.Context, client *apiclient.APIClient) ([]apiclient.SnapshotDto, error) {
+	var snapshots []apiclient.SnapshotDto
+
+	for page := 1; ; page++ {
+		paginated, httpResp, err := client.SnapshotsAPI.GetAllSnapshots(ctx).Page(float32(page)).Limit(pageSize).Execute()
+		if httpResp != nil && httpResp.Body != nil {
+			httpResp.Body.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, snapshot := range paginated.Items {
+			if !snapshot.General {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+
+		if float32(page) >= paginated.TotalPages {
+			return snapshots, nil
+		}
+	}
+}
+
+func writeSnapshots(w io.Writer, snapshots []apiclient.SnapshotDto, destroyBehavior string) {
+	labels := map[string]int{}
+
+	for i, snapshot := range snapshots {
+		label := resourceLabel(snapshot.Name)
+		labels[label]++
+		if labels[label] > 1 {
+			label = fmt.Sprintf("%s_%d", label, labels[label])
+		}
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "import {\n")
+		fmt.Fprintf(w, "  to = daytona_snapshot.%s\n", label)
+		fmt.Fprintf(w, "  id = %q\n", snapshot.Id)
+		fmt.Fprintf(w, "}\n\n")
+
+		attributes := [][2]string{{"name", fmt.Sprintf("%q", snapshot.Name)}}
+		if snapshot.ImageName != nil {
+			attributes = append(attributes, [2]string{"remote_image_name", fmt.Sprintf("%q", *snapshot.ImageName)})
+		} else {
+			// without a registry image the local image it was built from can't be
+			// known, the empty image_name matches the imported state
+			attributes = append(attributes, [2]string{"image_name", `""`})
+		}
+		attributes = append(attributes,
+			[2]string{"cpu", fmt.Sprintf("%d", int32(snapshot.Cpu))},
+			[2]string{"memory", fmt.Sprintf("%d", int32(snapshot.Mem))},
+			[2]string{"disk", fmt.Sprintf("%d", int32(snapshot.Disk))},
+		)
+		if snapshot.Gpu > 0 {
+			attributes = append(attributes, [2]string{"gpu", fmt.Sprintf("%d", int32(snapshot.Gpu))})
+		}
+		// delete is the default
+		if destroyBehavior != "delete" {
+			attributes = append(attributes, [2]string{"destroy_behavior", fmt.Sprintf("%q", destroyBehavior)})
+		}
+
+		width := 0
+		for _, attribute := range attributes {
+			width = max(width, len(attribute[0]))
+		}
+
+		fmt.Fprintf(w, "resource \"daytona_snapshot\" %q {\n", label)
+		for _, attribute := range attributes {
+			fmt.Fprintf(w, "  %-*s = %s\n", width, attribute[0], attribute[1])
+		}
+		fmt.Fprintf(w, "}\n")
+	}
+}
+
+// resourceLabel turns a snapshot name into a valid Terraform resource name.
+func resourceLabel(name string) string {
+	label := strings.Trim(invalidLabelCharacters.ReplaceAllString(name, "_"), "_")
+	if label == "" || !unicode.IsLetter(rune(label[0])) {
+		label = "snapshot_" + label
+	}
+	return label
+}
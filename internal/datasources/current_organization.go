@@ -0,0 +1,104 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ datasource.DataSource = &CurrentOrganizationDataSource{}
+
+func NewCurrentOrganizationDataSource() datasource.DataSource {
+	return &CurrentOrganizationDataSource{}
+}
+
+type CurrentOrganizationDataSource struct {
+	client *daytona.Client
+}
+
+type CurrentOrganizationDataSourceModel struct {
+	Id        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	Personal  types.Bool   `tfsdk:"personal"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+func (d *CurrentOrganizationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_current_organization"
+}
+
+func (d *CurrentOrganizationDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches the organization the provider is configured for, whether it was given by ID or resolved from its name",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the organization",
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the organization",
+				Computed:            true,
+			},
+			"personal": schema.BoolAttribute{
+				MarkdownDescription: "Whether the organization is the personal organization of a user",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The creation timestamp of the organization",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *CurrentOrganizationDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *CurrentOrganizationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CurrentOrganizationDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organization, httpResp, err := d.client.OrganizationsAPI.GetOrganization(ctx, d.client.OrganizationID).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to read organization, got error: %s", err),
+		)
+		return
+	}
+
+	data.Id = types.StringValue(organization.Id)
+	data.Name = types.StringValue(organization.Name)
+	data.Personal = types.BoolValue(organization.Personal)
+	data.CreatedAt = types.StringValue(organization.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,163 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ datasource.DataSource = &DefaultSnapshotDataSource{}
+
+func NewDefaultSnapshotDataSource() datasource.DataSource {
+	return &DefaultSnapshotDataSource{}
+}
+
+type DefaultSnapshotDataSource struct {
+	client *daytona.Client
+}
+
+type DefaultSnapshotDataSourceModel struct {
+	Id         types.String  `tfsdk:"id"`
+	Name       types.String  `tfsdk:"name"`
+	ImageName  types.String  `tfsdk:"image_name"`
+	State      types.String  `tfsdk:"state"`
+	Entrypoint types.List    `tfsdk:"entrypoint"`
+	Size       types.Float32 `tfsdk:"size"`
+	Cpu        types.Int32   `tfsdk:"cpu"`
+	Gpu        types.Int32   `tfsdk:"gpu"`
+	Memory     types.Int32   `tfsdk:"memory"`
+	Disk       types.Int32   `tfsdk:"disk"`
+	CreatedAt  types.String  `tfsdk:"created_at"`
+}
+
+func (d *DefaultSnapshotDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_default_snapshot"
+}
+
+func (d *DefaultSnapshotDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches information about a general snapshot that Daytona provides to all organizations. Snapshots of the organization itself are never matched, even if they have the same name",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the snapshot",
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the snapshot",
+				Required:            true,
+			},
+			"image_name": schema.StringAttribute{
+				MarkdownDescription: "The container image name for the snapshot",
+				Computed:            true,
+			},
+			"state": schema.StringAttribute{
+				MarkdownDescription: "The state of the snapshot",
+				Computed:            true,
+			},
+			"entrypoint": schema.ListAttribute{
+				MarkdownDescription: "The entrypoint command for the snapshot",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"size": schema.Float32Attribute{
+				MarkdownDescription: "The size of the snapshot in bytes",
+				Computed:            true,
+			},
+			"cpu": schema.Int32Attribute{
+				MarkdownDescription: "CPU cores allocated to the resulting sandbox",
+				Computed:            true,
+			},
+			"gpu": schema.Int32Attribute{
+				MarkdownDescription: "GPU units allocated to the resulting sandbox",
+				Computed:            true,
+			},
+			"memory": schema.Int32Attribute{
+				MarkdownDescription: "Memory allocated to the resulting sandbox in GB",
+				Computed:            true,
+			},
+			"disk": schema.Int32Attribute{
+				MarkdownDescription: "Disk space allocated to the resulting sandbox in GB",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The creation timestamp of the snapshot",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *DefaultSnapshotDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *DefaultSnapshotDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DefaultSnapshotDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	snapshots, err := listSnapshots(ctx, d.client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to list snapshots, got error: %s", err),
+		)
+		return
+	}
+
+	var snapshot *apiclient.SnapshotDto
+	for i := range snapshots {
+		if snapshots[i].General && snapshots[i].Name == data.Name.ValueString() {
+			snapshot = &snapshots[i]
+			break
+		}
+	}
+
+	if snapshot == nil {
+		resp.Diagnostics.AddError(
+			"Snapshot Not Found",
+			fmt.Sprintf("No general snapshot named %q found", data.Name.ValueString()),
+		)
+		return
+	}
+
+	data.Id = types.StringValue(snapshot.Id)
+	data.ImageName = types.StringPointerValue(snapshot.ImageName)
+	data.State = types.StringValue(string(snapshot.State))
+	data.Size = types.Float32PointerValue(snapshot.Size.Get())
+	data.Cpu = types.Int32Value(int32(snapshot.Cpu))
+	data.Gpu = types.Int32Value(int32(snapshot.Gpu))
+	data.Memory = types.Int32Value(int32(snapshot.Mem))
+	data.Disk = types.Int32Value(int32(snapshot.Disk))
+	data.CreatedAt = types.StringValue(snapshot.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
+
+	entrypoint, diags := types.ListValueFrom(ctx, types.StringType, snapshot.Entrypoint)
+	resp.Diagnostics.Append(diags...)
+	data.Entrypoint = entrypoint
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,161 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ datasource.DataSource = &OrganizationDataSource{}
+
+func NewOrganizationDataSource() datasource.DataSource {
+	return &OrganizationDataSource{}
+}
+
+type OrganizationDataSource struct {
+	client *daytona.Client
+}
+
+type OrganizationDataSourceModel struct {
+	Id               types.String `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	CreatedBy        types.String `tfsdk:"created_by"`
+	Personal         types.Bool   `tfsdk:"personal"`
+	CreatedAt        types.String `tfsdk:"created_at"`
+	MemberCount      types.Int64  `tfsdk:"member_count"`
+	Suspended        types.Bool   `tfsdk:"suspended"`
+	SuspendedAt      types.String `tfsdk:"suspended_at"`
+	SuspendedUntil   types.String `tfsdk:"suspended_until"`
+	SuspensionReason types.String `tfsdk:"suspension_reason"`
+}
+
+func (d *OrganizationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_organization"
+}
+
+func (d *OrganizationDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches information about a Daytona organization, e.g. to check that the provider points at the expected organization before creating resources",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the organization. Defaults to the organization of the provider",
+				Optional:            true,
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the organization",
+				Computed:            true,
+			},
+			"created_by": schema.StringAttribute{
+				MarkdownDescription: "The ID of the user who created the organization",
+				Computed:            true,
+			},
+			"personal": schema.BoolAttribute{
+				MarkdownDescription: "Whether the organization is the personal organization of a user",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The creation timestamp of the organization",
+				Computed:            true,
+			},
+			"member_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of members of the organization",
+				Computed:            true,
+			},
+			"suspended": schema.BoolAttribute{
+				MarkdownDescription: "Whether the organization is suspended",
+				Computed:            true,
+			},
+			"suspended_at": schema.StringAttribute{
+				MarkdownDescription: "When the organization was suspended. Null if it isn't suspended",
+				Computed:            true,
+			},
+			"suspended_until": schema.StringAttribute{
+				MarkdownDescription: "When the suspension of the organization ends. Null if it isn't suspended",
+				Computed:            true,
+			},
+			"suspension_reason": schema.StringAttribute{
+				MarkdownDescription: "Why the organization is suspended. Null if it isn't suspended",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *OrganizationDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *OrganizationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data OrganizationDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organizationID := d.client.OrganizationID
+	if !data.Id.IsNull() {
+		organizationID = data.Id.ValueString()
+	}
+
+	organization, httpResp, err := d.client.OrganizationsAPI.GetOrganization(ctx, organizationID).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to read organization, got error: %s", err),
+		)
+		return
+	}
+
+	members, httpResp, err := d.client.OrganizationsAPI.ListOrganizationMembers(ctx, organizationID).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to list organization members, got error: %s", err),
+		)
+		return
+	}
+
+	data.Id = types.StringValue(organization.Id)
+	data.Name = types.StringValue(organization.Name)
+	data.CreatedBy = types.StringValue(organization.CreatedBy)
+	data.Personal = types.BoolValue(organization.Personal)
+	data.CreatedAt = types.StringValue(organization.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
+	data.MemberCount = types.Int64Value(int64(len(members)))
+	data.Suspended = types.BoolValue(organization.Suspended)
+
+	if organization.Suspended {
+		data.SuspendedAt = types.StringValue(organization.SuspendedAt.Format("2006-01-02T15:04:05Z07:00"))
+		data.SuspendedUntil = types.StringValue(organization.SuspendedUntil.Format("2006-01-02T15:04:05Z07:00"))
+		data.SuspensionReason = types.StringValue(organization.SuspensionReason)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
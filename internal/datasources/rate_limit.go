@@ -0,0 +1,184 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ datasource.DataSource = &RateLimitDataSource{}
+
+func NewRateLimitDataSource() datasource.DataSource {
+	return &RateLimitDataSource{}
+}
+
+type RateLimitDataSource struct {
+	client *daytona.Client
+}
+
+type RateLimitDataSourceModel struct {
+	OrganizationId    types.String  `tfsdk:"organization_id"`
+	RequestLimit      types.Int64   `tfsdk:"request_limit"`
+	RequestsRemaining types.Int64   `tfsdk:"requests_remaining"`
+	ResetSeconds      types.Int64   `tfsdk:"reset_seconds"`
+	CpuQuota          types.Float32 `tfsdk:"cpu_quota"`
+	CpuUsage          types.Float32 `tfsdk:"cpu_usage"`
+	CpuAvailable      types.Float32 `tfsdk:"cpu_available"`
+	MemoryQuota       types.Float32 `tfsdk:"memory_quota"`
+	MemoryUsage       types.Float32 `tfsdk:"memory_usage"`
+	MemoryAvailable   types.Float32 `tfsdk:"memory_available"`
+	DiskQuota         types.Float32 `tfsdk:"disk_quota"`
+	DiskUsage         types.Float32 `tfsdk:"disk_usage"`
+	DiskAvailable     types.Float32 `tfsdk:"disk_available"`
+	GpuQuota          types.Float32 `tfsdk:"gpu_quota"`
+}
+
+func (d *RateLimitDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rate_limit"
+}
+
+func (d *RateLimitDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches the current API rate-limit and resource quota headroom of the configured organization",
+
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The organization ID the limits apply to",
+				Computed:            true,
+			},
+			"request_limit": schema.Int64Attribute{
+				MarkdownDescription: "Number of API requests allowed in the current rate-limit window. Null if the API does not report it",
+				Computed:            true,
+			},
+			"requests_remaining": schema.Int64Attribute{
+				MarkdownDescription: "Number of API requests left in the current rate-limit window. Null if the API does not report it",
+				Computed:            true,
+			},
+			"reset_seconds": schema.Int64Attribute{
+				MarkdownDescription: "Seconds until the current rate-limit window resets. Null if the API does not report it",
+				Computed:            true,
+			},
+			"cpu_quota": schema.Float32Attribute{
+				MarkdownDescription: "Total CPU cores available to the organization",
+				Computed:            true,
+			},
+			"cpu_usage": schema.Float32Attribute{
+				MarkdownDescription: "CPU cores currently in use by the organization",
+				Computed:            true,
+			},
+			"cpu_available": schema.Float32Attribute{
+				MarkdownDescription: "CPU cores that can still be allocated",
+				Computed:            true,
+			},
+			"memory_quota": schema.Float32Attribute{
+				MarkdownDescription: "Total memory available to the organization in GB",
+				Computed:            true,
+			},
+			"memory_usage": schema.Float32Attribute{
+				MarkdownDescription: "Memory currently in use by the organization in GB",
+				Computed:            true,
+			},
+			"memory_available": schema.Float32Attribute{
+				MarkdownDescription: "Memory that can still be allocated in GB",
+				Computed:            true,
+			},
+			"disk_quota": schema.Float32Attribute{
+				MarkdownDescription: "Total disk space available to the organization in GB",
+				Computed:            true,
+			},
+			"disk_usage": schema.Float32Attribute{
+				MarkdownDescription: "Disk space currently in use by the organization in GB",
+				Computed:            true,
+			},
+			"disk_available": schema.Float32Attribute{
+				MarkdownDescription: "Disk space that can still be allocated in GB",
+				Computed:            true,
+			},
+			"gpu_quota": schema.Float32Attribute{
+				MarkdownDescription: "Total GPU units available to the organization",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *RateLimitDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *RateLimitDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RateLimitDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	usage, httpResp, err := d.client.OrganizationsAPI.GetOrganizationUsageOverview(ctx, d.client.OrganizationID).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil && httpResp != nil && httpResp.StatusCode == http.StatusTooManyRequests {
+		resp.Diagnostics.AddError(
+			"Rate Limit Exceeded",
+			fmt.Sprintf("The Daytona API rate limit is exhausted, retry after %s seconds", httpResp.Header.Get("Retry-After")),
+		)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to read organization usage, got error: %s", err),
+		)
+		return
+	}
+
+	data.OrganizationId = types.StringValue(d.client.OrganizationID)
+	data.RequestLimit = rateLimitHeader(httpResp.Header, "X-RateLimit-Limit")
+	data.RequestsRemaining = rateLimitHeader(httpResp.Header, "X-RateLimit-Remaining")
+	data.ResetSeconds = rateLimitHeader(httpResp.Header, "X-RateLimit-Reset")
+
+	data.CpuQuota = types.Float32Value(usage.TotalCpuQuota)
+	data.CpuUsage = types.Float32Value(usage.CurrentCpuUsage)
+	data.CpuAvailable = types.Float32Value(usage.TotalCpuQuota - usage.CurrentCpuUsage)
+	data.MemoryQuota = types.Float32Value(usage.TotalMemoryQuota)
+	data.MemoryUsage = types.Float32Value(usage.CurrentMemoryUsage)
+	data.MemoryAvailable = types.Float32Value(usage.TotalMemoryQuota - usage.CurrentMemoryUsage)
+	data.DiskQuota = types.Float32Value(usage.TotalDiskQuota)
+	data.DiskUsage = types.Float32Value(usage.CurrentDiskUsage)
+	data.DiskAvailable = types.Float32Value(usage.TotalDiskQuota - usage.CurrentDiskUsage)
+	data.GpuQuota = types.Float32Value(usage.TotalGpuQuota)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// rateLimitHeader returns the numeric value of a rate-limit response header,
+// or null when the API didn't send it.
+func rateLimitHeader(header http.Header, name string) types.Int64 {
+	value, err := strconv.ParseInt(header.Get(name), 10, 64)
+	if err != nil {
+		return types.Int64Null()
+	}
+
+	return types.Int64Value(value)
+}
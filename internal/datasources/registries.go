@@ -0,0 +1,155 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ datasource.DataSource = &RegistriesDataSource{}
+
+func NewRegistriesDataSource() datasource.DataSource {
+	return &RegistriesDataSource{}
+}
+
+type RegistriesDataSource struct {
+	client *daytona.Client
+}
+
+type RegistriesDataSourceModel struct {
+	Name       types.String          `tfsdk:"name"`
+	Registries []RegistriesItemModel `tfsdk:"registries"`
+}
+
+type RegistriesItemModel struct {
+	Id           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Url          types.String `tfsdk:"url"`
+	Username     types.String `tfsdk:"username"`
+	Project      types.String `tfsdk:"project"`
+	RegistryType types.String `tfsdk:"registry_type"`
+	CreatedAt    types.String `tfsdk:"created_at"`
+}
+
+func (d *RegistriesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registries"
+}
+
+func (d *RegistriesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the container registries registered in the organization, sorted by name. Registry passwords are never returned",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Only list registries with this name",
+				Optional:            true,
+			},
+			"registries": schema.ListNestedAttribute{
+				MarkdownDescription: "The matching registries",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the registry",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the registry",
+							Computed:            true,
+						},
+						"url": schema.StringAttribute{
+							MarkdownDescription: "The URL of the registry",
+							Computed:            true,
+						},
+						"username": schema.StringAttribute{
+							MarkdownDescription: "The username used to authenticate with the registry",
+							Computed:            true,
+						},
+						"project": schema.StringAttribute{
+							MarkdownDescription: "The project within the registry",
+							Computed:            true,
+						},
+						"registry_type": schema.StringAttribute{
+							MarkdownDescription: "The type of the registry",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "The creation timestamp of the registry",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RegistriesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *RegistriesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RegistriesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registries, httpResp, err := d.client.DockerRegistryAPI.ListRegistries(ctx).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to list registries, got error: %s", err),
+		)
+		return
+	}
+
+	slices.SortFunc(registries, func(a, b apiclient.DockerRegistry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	data.Registries = []RegistriesItemModel{}
+	for _, registry := range registries {
+		if !data.Name.IsNull() && registry.Name != data.Name.ValueString() {
+			continue
+		}
+
+		data.Registries = append(data.Registries, RegistriesItemModel{
+			Id:           types.StringValue(registry.Id),
+			Name:         types.StringValue(registry.Name),
+			Url:          types.StringValue(registry.Url),
+			Username:     types.StringValue(registry.Username),
+			Project:      types.StringValue(registry.Project),
+			RegistryType: types.StringValue(registry.RegistryType),
+			CreatedAt:    types.StringValue(registry.CreatedAt.Format("2006-01-02T15:04:05Z07:00")),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
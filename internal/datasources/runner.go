@@ -0,0 +1,264 @@
+package datasources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ datasource.DataSource = &RunnerDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &RunnerDataSource{}
+
+func NewRunnerDataSource() datasource.DataSource {
+	return &RunnerDataSource{}
+}
+
+type RunnerDataSource struct {
+	client *daytona.Client
+}
+
+type RunnerDataSourceModel struct {
+	Id                           types.String  `tfsdk:"id"`
+	Domain                       types.String  `tfsdk:"domain"`
+	Region                       types.String  `tfsdk:"region"`
+	Class                        types.String  `tfsdk:"class"`
+	State                        types.String  `tfsdk:"state"`
+	Unschedulable                types.Bool    `tfsdk:"unschedulable"`
+	Version                      types.String  `tfsdk:"version"`
+	Cpu                          types.Float32 `tfsdk:"cpu"`
+	Memory                       types.Float32 `tfsdk:"memory"`
+	Disk                         types.Float32 `tfsdk:"disk"`
+	Gpu                          types.Float32 `tfsdk:"gpu"`
+	GpuType                      types.String  `tfsdk:"gpu_type"`
+	Capacity                     types.Float32 `tfsdk:"capacity"`
+	Used                         types.Float32 `tfsdk:"used"`
+	CurrentCpuUsagePercentage    types.Float32 `tfsdk:"current_cpu_usage_percentage"`
+	CurrentMemoryUsagePercentage types.Float32 `tfsdk:"current_memory_usage_percentage"`
+	CurrentDiskUsagePercentage   types.Float32 `tfsdk:"current_disk_usage_percentage"`
+	CurrentAllocatedCpu          types.Float32 `tfsdk:"current_allocated_cpu"`
+	CurrentAllocatedMemory       types.Float32 `tfsdk:"current_allocated_memory"`
+	CurrentAllocatedDisk         types.Float32 `tfsdk:"current_allocated_disk"`
+	CurrentSnapshotCount         types.Float32 `tfsdk:"current_snapshot_count"`
+	AvailabilityScore            types.Float32 `tfsdk:"availability_score"`
+	LastChecked                  types.String  `tfsdk:"last_checked"`
+}
+
+func (d *RunnerDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_runner"
+}
+
+func (d *RunnerDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches capacity and load information about a single Daytona runner, looked up by ID or domain",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the runner. Conflicts with `domain`",
+				Optional:            true,
+				Computed:            true,
+			},
+			"domain": schema.StringAttribute{
+				MarkdownDescription: "The domain of the runner. Conflicts with `id`",
+				Optional:            true,
+				Computed:            true,
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "The region the runner is located in",
+				Computed:            true,
+			},
+			"class": schema.StringAttribute{
+				MarkdownDescription: "The sandbox class supported by the runner",
+				Computed:            true,
+			},
+			"state": schema.StringAttribute{
+				MarkdownDescription: "The state of the runner",
+				Computed:            true,
+			},
+			"unschedulable": schema.BoolAttribute{
+				MarkdownDescription: "Whether new sandboxes are prevented from being scheduled on the runner",
+				Computed:            true,
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "The runner software version",
+				Computed:            true,
+			},
+			"cpu": schema.Float32Attribute{
+				MarkdownDescription: "CPU cores of the runner",
+				Computed:            true,
+			},
+			"memory": schema.Float32Attribute{
+				MarkdownDescription: "Memory of the runner in GB",
+				Computed:            true,
+			},
+			"disk": schema.Float32Attribute{
+				MarkdownDescription: "Disk space of the runner in GB",
+				Computed:            true,
+			},
+			"gpu": schema.Float32Attribute{
+				MarkdownDescription: "GPU units of the runner",
+				Computed:            true,
+			},
+			"gpu_type": schema.StringAttribute{
+				MarkdownDescription: "The type of GPU of the runner",
+				Computed:            true,
+			},
+			"capacity": schema.Float32Attribute{
+				MarkdownDescription: "The sandbox capacity of the runner",
+				Computed:            true,
+			},
+			"used": schema.Float32Attribute{
+				MarkdownDescription: "The used sandbox capacity of the runner",
+				Computed:            true,
+			},
+			"current_cpu_usage_percentage": schema.Float32Attribute{
+				MarkdownDescription: "Current CPU usage of the runner in percent",
+				Computed:            true,
+			},
+			"current_memory_usage_percentage": schema.Float32Attribute{
+				MarkdownDescription: "Current memory usage of the runner in percent",
+				Computed:            true,
+			},
+			"current_disk_usage_percentage": schema.Float32Attribute{
+				MarkdownDescription: "Current disk usage of the runner in percent",
+				Computed:            true,
+			},
+			"current_allocated_cpu": schema.Float32Attribute{
+				MarkdownDescription: "CPU cores currently allocated to sandboxes",
+				Computed:            true,
+			},
+			"current_allocated_memory": schema.Float32Attribute{
+				MarkdownDescription: "Memory currently allocated to sandboxes in GB",
+				Computed:            true,
+			},
+			"current_allocated_disk": schema.Float32Attribute{
+				MarkdownDescription: "Disk space currently allocated to sandboxes in GB",
+				Computed:            true,
+			},
+			"current_snapshot_count": schema.Float32Attribute{
+				MarkdownDescription: "Number of snapshots currently present on the runner",
+				Computed:            true,
+			},
+			"availability_score": schema.Float32Attribute{
+				MarkdownDescription: "Scheduling score of the runner, higher means more available",
+				Computed:            true,
+			},
+			"last_checked": schema.StringAttribute{
+				MarkdownDescription: "The timestamp of the last runner health check",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *RunnerDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("domain")),
+	}
+}
+
+func (d *RunnerDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *RunnerDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RunnerDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	runners, err := listRunners(ctx, d.client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to list runners, got error: %s", err),
+		)
+		return
+	}
+
+	var runner *apiclient.Runner
+	for i := range runners {
+		if (!data.Id.IsNull() && runners[i].Id == data.Id.ValueString()) ||
+			(!data.Domain.IsNull() && runners[i].Domain == data.Domain.ValueString()) {
+			runner = &runners[i]
+			break
+		}
+	}
+
+	if runner == nil {
+		resp.Diagnostics.AddError(
+			"Runner Not Found",
+			fmt.Sprintf("No runner with ID %q or domain %q found", data.Id.ValueString(), data.Domain.ValueString()),
+		)
+		return
+	}
+
+	data.Id = types.StringValue(runner.Id)
+	data.Domain = types.StringValue(runner.Domain)
+	data.Region = types.StringValue(runner.Region)
+	data.Class = types.StringValue(string(runner.Class))
+	data.State = types.StringValue(string(runner.State))
+	data.Unschedulable = types.BoolValue(runner.Unschedulable)
+	data.Version = types.StringValue(runner.Version)
+	data.Cpu = types.Float32Value(runner.Cpu)
+	data.Memory = types.Float32Value(runner.Memory)
+	data.Disk = types.Float32Value(runner.Disk)
+	data.Gpu = types.Float32Value(runner.Gpu)
+	data.GpuType = types.StringValue(runner.GpuType)
+	data.Capacity = types.Float32Value(runner.Capacity)
+	data.Used = types.Float32Value(runner.Used)
+	data.CurrentCpuUsagePercentage = types.Float32PointerValue(runner.CurrentCpuUsagePercentage)
+	data.CurrentMemoryUsagePercentage = types.Float32PointerValue(runner.CurrentMemoryUsagePercentage)
+	data.CurrentDiskUsagePercentage = types.Float32PointerValue(runner.CurrentDiskUsagePercentage)
+	data.CurrentAllocatedCpu = types.Float32PointerValue(runner.CurrentAllocatedCpu)
+	data.CurrentAllocatedMemory = types.Float32PointerValue(runner.CurrentAllocatedMemoryGiB)
+	data.CurrentAllocatedDisk = types.Float32PointerValue(runner.CurrentAllocatedDiskGiB)
+	data.CurrentSnapshotCount = types.Float32PointerValue(runner.CurrentSnapshotCount)
+	data.AvailabilityScore = types.Float32PointerValue(runner.AvailabilityScore)
+	data.LastChecked = types.StringPointerValue(runner.LastChecked)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// listRunners fetches all runners. The generated client doesn't decode the
+// response of this endpoint, so the body is decoded here.
+func listRunners(ctx context.Context, client *daytona.Client) ([]apiclient.Runner, error) {
+	httpResp, err := client.RunnersAPI.ListRunners(ctx).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		defer httpResp.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var runners []apiclient.Runner
+	if err := json.NewDecoder(httpResp.Body).Decode(&runners); err != nil {
+		return nil, fmt.Errorf("unable to decode runners: %w", err)
+	}
+
+	return runners, nil
+}
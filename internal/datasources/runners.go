@@ -0,0 +1,214 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ datasource.DataSource = &RunnersDataSource{}
+
+func NewRunnersDataSource() datasource.DataSource {
+	return &RunnersDataSource{}
+}
+
+type RunnersDataSource struct {
+	client *daytona.Client
+}
+
+type RunnersDataSourceModel struct {
+	Region  types.String       `tfsdk:"region"`
+	Class   types.String       `tfsdk:"class"`
+	Runners []RunnersItemModel `tfsdk:"runners"`
+}
+
+type RunnersItemModel struct {
+	Id                types.String  `tfsdk:"id"`
+	Domain            types.String  `tfsdk:"domain"`
+	Region            types.String  `tfsdk:"region"`
+	Class             types.String  `tfsdk:"class"`
+	State             types.String  `tfsdk:"state"`
+	Unschedulable     types.Bool    `tfsdk:"unschedulable"`
+	Version           types.String  `tfsdk:"version"`
+	Cpu               types.Float32 `tfsdk:"cpu"`
+	Memory            types.Float32 `tfsdk:"memory"`
+	Disk              types.Float32 `tfsdk:"disk"`
+	Gpu               types.Float32 `tfsdk:"gpu"`
+	GpuType           types.String  `tfsdk:"gpu_type"`
+	Capacity          types.Float32 `tfsdk:"capacity"`
+	Used              types.Float32 `tfsdk:"used"`
+	AvailabilityScore types.Float32 `tfsdk:"availability_score"`
+	LastChecked       types.String  `tfsdk:"last_checked"`
+}
+
+func (d *RunnersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_runners"
+}
+
+func (d *RunnersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the Daytona runners with their capacity and health, sorted by ID. Use `daytona_runner` for the current load of a single runner",
+
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				MarkdownDescription: "Only list runners located in this region",
+				Optional:            true,
+			},
+			"class": schema.StringAttribute{
+				MarkdownDescription: "Only list runners supporting this sandbox class",
+				Optional:            true,
+			},
+			"runners": schema.ListNestedAttribute{
+				MarkdownDescription: "The matching runners",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the runner",
+							Computed:            true,
+						},
+						"domain": schema.StringAttribute{
+							MarkdownDescription: "The domain of the runner",
+							Computed:            true,
+						},
+						"region": schema.StringAttribute{
+							MarkdownDescription: "The region the runner is located in",
+							Computed:            true,
+						},
+						"class": schema.StringAttribute{
+							MarkdownDescription: "The sandbox class supported by the runner",
+							Computed:            true,
+						},
+						"state": schema.StringAttribute{
+							MarkdownDescription: "The state of the runner",
+							Computed:            true,
+						},
+						"unschedulable": schema.BoolAttribute{
+							MarkdownDescription: "Whether new sandboxes are prevented from being scheduled on the runner",
+							Computed:            true,
+						},
+						"version": schema.StringAttribute{
+							MarkdownDescription: "The runner software version",
+							Computed:            true,
+						},
+						"cpu": schema.Float32Attribute{
+							MarkdownDescription: "CPU cores of the runner",
+							Computed:            true,
+						},
+						"memory": schema.Float32Attribute{
+							MarkdownDescription: "Memory of the runner in GB",
+							Computed:            true,
+						},
+						"disk": schema.Float32Attribute{
+							MarkdownDescription: "Disk space of the runner in GB",
+							Computed:            true,
+						},
+						"gpu": schema.Float32Attribute{
+							MarkdownDescription: "GPU units of the runner",
+							Computed:            true,
+						},
+						"gpu_type": schema.StringAttribute{
+							MarkdownDescription: "The type of GPU of the runner",
+							Computed:            true,
+						},
+						"capacity": schema.Float32Attribute{
+							MarkdownDescription: "The sandbox capacity of the runner",
+							Computed:            true,
+						},
+						"used": schema.Float32Attribute{
+							MarkdownDescription: "The used sandbox capacity of the runner",
+							Computed:            true,
+						},
+						"availability_score": schema.Float32Attribute{
+							MarkdownDescription: "Scheduling score of the runner, higher means more available",
+							Computed:            true,
+						},
+						"last_checked": schema.StringAttribute{
+							MarkdownDescription: "The timestamp of the last runner health check",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RunnersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *RunnersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RunnersDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	runners, err := listRunners(ctx, d.client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to list runners, got error: %s", err),
+		)
+		return
+	}
+
+	slices.SortFunc(runners, func(a, b apiclient.Runner) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	data.Runners = []RunnersItemModel{}
+	for _, runner := range runners {
+		if !data.Region.IsNull() && runner.Region != data.Region.ValueString() {
+			continue
+		}
+		if !data.Class.IsNull() && string(runner.Class) != data.Class.ValueString() {
+			continue
+		}
+
+		data.Runners = append(data.Runners, RunnersItemModel{
+			Id:                types.StringValue(runner.Id),
+			Domain:            types.StringValue(runner.Domain),
+			Region:            types.StringValue(runner.Region),
+			Class:             types.StringValue(string(runner.Class)),
+			State:             types.StringValue(string(runner.State)),
+			Unschedulable:     types.BoolValue(runner.Unschedulable),
+			Version:           types.StringValue(runner.Version),
+			Cpu:               types.Float32Value(runner.Cpu),
+			Memory:            types.Float32Value(runner.Memory),
+			Disk:              types.Float32Value(runner.Disk),
+			Gpu:               types.Float32Value(runner.Gpu),
+			GpuType:           types.StringValue(runner.GpuType),
+			Capacity:          types.Float32Value(runner.Capacity),
+			Used:              types.Float32Value(runner.Used),
+			AvailabilityScore: types.Float32PointerValue(runner.AvailabilityScore),
+			LastChecked:       types.StringPointerValue(runner.LastChecked),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,268 @@
+package datasources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ datasource.DataSource = &SandboxesDataSource{}
+
+func NewSandboxesDataSource() datasource.DataSource {
+	return &SandboxesDataSource{}
+}
+
+type SandboxesDataSource struct {
+	client *daytona.Client
+}
+
+type SandboxesDataSourceModel struct {
+	Labels    types.Map            `tfsdk:"labels"`
+	States    types.List           `tfsdk:"states"`
+	Sandboxes []SandboxesItemModel `tfsdk:"sandboxes"`
+}
+
+type SandboxesItemModel struct {
+	Id               types.String  `tfsdk:"id"`
+	Snapshot         types.String  `tfsdk:"snapshot"`
+	User             types.String  `tfsdk:"user"`
+	Target           types.String  `tfsdk:"target"`
+	State            types.String  `tfsdk:"state"`
+	ErrorReason      types.String  `tfsdk:"error_reason"`
+	Labels           types.Map     `tfsdk:"labels"`
+	Public           types.Bool    `tfsdk:"public"`
+	Cpu              types.Float32 `tfsdk:"cpu"`
+	Gpu              types.Float32 `tfsdk:"gpu"`
+	Memory           types.Float32 `tfsdk:"memory"`
+	Disk             types.Float32 `tfsdk:"disk"`
+	AutoStopInterval types.Float32 `tfsdk:"auto_stop_interval"`
+	CreatedAt        types.String  `tfsdk:"created_at"`
+}
+
+// erroredDeletedSandboxStates are only listed by the API when asked for
+// explicitly.
+var erroredDeletedSandboxStates = []string{
+	string(apiclient.SANDBOXSTATE_ERROR),
+	string(apiclient.SANDBOXSTATE_BUILD_FAILED),
+	string(apiclient.SANDBOXSTATE_DESTROYED),
+}
+
+func (d *SandboxesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sandboxes"
+}
+
+func (d *SandboxesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	states := make([]string, 0, len(apiclient.AllowedSandboxStateEnumValues))
+	for _, state := range apiclient.AllowedSandboxStateEnumValues {
+		states = append(states, string(state))
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the sandboxes of the organization, sorted by ID",
+
+		Attributes: map[string]schema.Attribute{
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Only list sandboxes that have all of these labels",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"states": schema.ListAttribute{
+				MarkdownDescription: "Only list sandboxes in one of these states, e.g. `started`. Errored and destroyed sandboxes are only listed when their state is given here",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(states...)),
+				},
+			},
+			"sandboxes": schema.ListNestedAttribute{
+				MarkdownDescription: "The matching sandboxes",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the sandbox",
+							Computed:            true,
+						},
+						"snapshot": schema.StringAttribute{
+							MarkdownDescription: "The snapshot the sandbox was created from",
+							Computed:            true,
+						},
+						"user": schema.StringAttribute{
+							MarkdownDescription: "The OS user running in the sandbox",
+							Computed:            true,
+						},
+						"target": schema.StringAttribute{
+							MarkdownDescription: "The region the sandbox runs in",
+							Computed:            true,
+						},
+						"state": schema.StringAttribute{
+							MarkdownDescription: "The state of the sandbox",
+							Computed:            true,
+						},
+						"error_reason": schema.StringAttribute{
+							MarkdownDescription: "Why the sandbox is in an error state",
+							Computed:            true,
+						},
+						"labels": schema.MapAttribute{
+							MarkdownDescription: "The labels of the sandbox",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"public": schema.BoolAttribute{
+							MarkdownDescription: "Whether the previews of the sandbox are public",
+							Computed:            true,
+						},
+						"cpu": schema.Float32Attribute{
+							MarkdownDescription: "CPU cores allocated to the sandbox",
+							Computed:            true,
+						},
+						"gpu": schema.Float32Attribute{
+							MarkdownDescription: "GPU units allocated to the sandbox",
+							Computed:            true,
+						},
+						"memory": schema.Float32Attribute{
+							MarkdownDescription: "Memory allocated to the sandbox in GB",
+							Computed:            true,
+						},
+						"disk": schema.Float32Attribute{
+							MarkdownDescription: "Disk space allocated to the sandbox in GB",
+							Computed:            true,
+						},
+						"auto_stop_interval": schema.Float32Attribute{
+							MarkdownDescription: "Minutes of inactivity after which the sandbox is stopped, 0 if it never is",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "The creation timestamp of the sandbox",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *SandboxesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *SandboxesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SandboxesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var states []string
+	if !data.States.IsNull() {
+		resp.Diagnostics.Append(data.States.ElementsAs(ctx, &states, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	listRequest := d.client.SandboxAPI.ListSandboxes(ctx)
+
+	if !data.Labels.IsNull() {
+		labels := map[string]string{}
+		resp.Diagnostics.Append(data.Labels.ElementsAs(ctx, &labels, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// the API expects the labels as a JSON object in the query
+		encoded, err := json.Marshal(labels)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to encode labels, got error: %s", err))
+			return
+		}
+		listRequest = listRequest.Labels(string(encoded))
+	}
+
+	if slices.ContainsFunc(states, func(state string) bool { return slices.Contains(erroredDeletedSandboxStates, state) }) {
+		listRequest = listRequest.IncludeErroredDeleted(true)
+	}
+
+	sandboxes, httpResp, err := listRequest.Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to list sandboxes, got error: %s", err),
+		)
+		return
+	}
+
+	slices.SortFunc(sandboxes, func(a, b apiclient.Sandbox) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	data.Sandboxes = []SandboxesItemModel{}
+	for _, sandbox := range sandboxes {
+		if len(states) > 0 && (sandbox.State == nil || !slices.Contains(states, string(*sandbox.State))) {
+			continue
+		}
+
+		item, diags := sandboxesItem(ctx, &sandbox)
+		resp.Diagnostics.Append(diags...)
+		data.Sandboxes = append(data.Sandboxes, item)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func sandboxesItem(ctx context.Context, sandbox *apiclient.Sandbox) (item SandboxesItemModel, diags diag.Diagnostics) {
+	item = SandboxesItemModel{
+		Id:               types.StringValue(sandbox.Id),
+		Snapshot:         types.StringPointerValue(sandbox.Snapshot),
+		User:             types.StringValue(sandbox.User),
+		Target:           types.StringValue(sandbox.Target),
+		State:            types.StringNull(),
+		ErrorReason:      types.StringPointerValue(sandbox.ErrorReason),
+		Public:           types.BoolValue(sandbox.Public),
+		Cpu:              types.Float32Value(sandbox.Cpu),
+		Gpu:              types.Float32Value(sandbox.Gpu),
+		Memory:           types.Float32Value(sandbox.Memory),
+		Disk:             types.Float32Value(sandbox.Disk),
+		AutoStopInterval: types.Float32PointerValue(sandbox.AutoStopInterval),
+		CreatedAt:        types.StringPointerValue(sandbox.CreatedAt),
+	}
+
+	if sandbox.State != nil {
+		item.State = types.StringValue(string(*sandbox.State))
+	}
+
+	item.Labels, diags = types.MapValueFrom(ctx, types.StringType, sandbox.Labels)
+	return
+}
@@ -4,34 +4,45 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
 )
 
 var _ datasource.DataSource = &SnapshotDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &SnapshotDataSource{}
 
 func NewSnapshotDataSource() datasource.DataSource {
 	return &SnapshotDataSource{}
 }
 
 type SnapshotDataSource struct {
-	client *apiclient.APIClient
+	client *daytona.Client
 }
 
 type SnapshotDataSourceModel struct {
-	Id             types.String  `tfsdk:"id"`
-	Name           types.String  `tfsdk:"name"`
-	ImageName      types.String  `tfsdk:"image_name"`
-	Entrypoint     types.List    `tfsdk:"entrypoint"`
-	OrganizationId types.String  `tfsdk:"organization_id"`
-	Size           types.Float32 `tfsdk:"size"`
-	Cpu            types.Int32   `tfsdk:"cpu"`
-	Gpu            types.Int32   `tfsdk:"gpu"`
-	Memory         types.Int32   `tfsdk:"memory"`
-	Disk           types.Int32   `tfsdk:"disk"`
-	CreatedAt      types.String  `tfsdk:"created_at"`
+	Id                 types.String  `tfsdk:"id"`
+	Name               types.String  `tfsdk:"name"`
+	ImageName          types.String  `tfsdk:"image_name"`
+	Entrypoint         types.List    `tfsdk:"entrypoint"`
+	OrganizationId     types.String  `tfsdk:"organization_id"`
+	Size               types.Float32 `tfsdk:"size"`
+	Cpu                types.Int32   `tfsdk:"cpu"`
+	Gpu                types.Int32   `tfsdk:"gpu"`
+	Memory             types.Int32   `tfsdk:"memory"`
+	Disk               types.Int32   `tfsdk:"disk"`
+	CreatedAt          types.String  `tfsdk:"created_at"`
+	State              types.String  `tfsdk:"state"`
+	ErrorReason        types.String  `tfsdk:"error_reason"`
+	LastUsedAt         types.String  `tfsdk:"last_used_at"`
+	DockerfileContent  types.String  `tfsdk:"dockerfile_content"`
+	BuildContextHashes types.List    `tfsdk:"build_context_hashes"`
+	AllowMissing       types.Bool    `tfsdk:"allow_missing"`
+	Exists             types.Bool    `tfsdk:"exists"`
 }
 
 func (d *SnapshotDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -40,16 +51,18 @@ func (d *SnapshotDataSource) Metadata(ctx context.Context, req datasource.Metada
 
 func (d *SnapshotDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Fetches information about a Daytona snapshot",
+		MarkdownDescription: "Fetches information about a Daytona snapshot, looked up by ID or name",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				MarkdownDescription: "The ID of the snapshot",
+				MarkdownDescription: "The ID of the snapshot. Conflicts with `name`",
+				Optional:            true,
 				Computed:            true,
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "The name of the snapshot",
-				Required:            true,
+				MarkdownDescription: "The name of the snapshot. Conflicts with `id`",
+				Optional:            true,
+				Computed:            true,
 			},
 			"image_name": schema.StringAttribute{
 				MarkdownDescription: "The container image name for the snapshot",
@@ -88,20 +101,55 @@ func (d *SnapshotDataSource) Schema(ctx context.Context, req datasource.SchemaRe
 				MarkdownDescription: "The creation timestamp of the snapshot",
 				Computed:            true,
 			},
+			"state": schema.StringAttribute{
+				MarkdownDescription: "The state of the snapshot, e.g. `active` or `error`",
+				Computed:            true,
+			},
+			"error_reason": schema.StringAttribute{
+				MarkdownDescription: "Why the snapshot is in an error state",
+				Computed:            true,
+			},
+			"last_used_at": schema.StringAttribute{
+				MarkdownDescription: "When a sandbox was last created from the snapshot, null if it never was",
+				Computed:            true,
+			},
+			"dockerfile_content": schema.StringAttribute{
+				MarkdownDescription: "The Dockerfile the snapshot was built from, null if it was created from an image",
+				Computed:            true,
+			},
+			"build_context_hashes": schema.ListAttribute{
+				MarkdownDescription: "Hashes of the build context files of the snapshot, null if it was created from an image",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"allow_missing": schema.BoolAttribute{
+				MarkdownDescription: "Whether a missing snapshot is reported through `exists` instead of failing. All other attributes are null when the snapshot is missing",
+				Optional:            true,
+			},
+			"exists": schema.BoolAttribute{
+				MarkdownDescription: "Whether the snapshot exists",
+				Computed:            true,
+			},
 		},
 	}
 }
 
+func (d *SnapshotDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("name")),
+	}
+}
+
 func (d *SnapshotDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*apiclient.APIClient)
+	client, ok := req.ProviderData.(*daytona.Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *apiclient.APIClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
@@ -118,11 +166,21 @@ func (d *SnapshotDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
-	snapshot, httpResp, err := d.client.SnapshotsAPI.GetSnapshot(ctx, data.Name.ValueString()).Execute()
+	// the API looks snapshots up by either ID or name
+	idOrName := data.Name.ValueString()
+	if !data.Id.IsNull() {
+		idOrName = data.Id.ValueString()
+	}
+
+	snapshot, httpResp, err := d.client.SnapshotsAPI.GetSnapshot(ctx, idOrName).Execute()
 	if httpResp != nil && httpResp.Body != nil {
 		httpResp.Body.Close()
 	}
-	if err != nil {
+	if err != nil && httpResp != nil && httpResp.StatusCode == 404 && data.AllowMissing.ValueBool() {
+		data.Exists = types.BoolValue(false)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	} else if err != nil {
 		resp.Diagnostics.AddError(
 			"Client Error",
 			fmt.Sprintf("Unable to read snapshot, got error: %s", err),
@@ -130,6 +188,7 @@ func (d *SnapshotDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
+	data.Exists = types.BoolValue(true)
 	data.Id = types.StringValue(snapshot.Id)
 	data.Name = types.StringValue(snapshot.Name)
 	data.Cpu = types.Int32Value(int32(snapshot.Cpu))
@@ -137,6 +196,20 @@ func (d *SnapshotDataSource) Read(ctx context.Context, req datasource.ReadReques
 	data.Memory = types.Int32Value(int32(snapshot.Mem))
 	data.Disk = types.Int32Value(int32(snapshot.Disk))
 	data.CreatedAt = types.StringValue(snapshot.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
+	data.State = types.StringValue(string(snapshot.State))
+	data.ErrorReason = types.StringPointerValue(snapshot.ErrorReason.Get())
+
+	if lastUsedAt := snapshot.LastUsedAt.Get(); lastUsedAt != nil {
+		data.LastUsedAt = types.StringValue(lastUsedAt.Format("2006-01-02T15:04:05Z07:00"))
+	}
+
+	if snapshot.BuildInfo != nil {
+		data.DockerfileContent = types.StringPointerValue(snapshot.BuildInfo.DockerfileContent)
+
+		contextHashes, diags := types.ListValueFrom(ctx, types.StringType, snapshot.BuildInfo.ContextHashes)
+		resp.Diagnostics.Append(diags...)
+		data.BuildContextHashes = contextHashes
+	}
 
 	if snapshot.OrganizationId != nil {
 		data.OrganizationId = types.StringValue(*snapshot.OrganizationId)
@@ -0,0 +1,259 @@
+package datasources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ datasource.DataSource = &SnapshotsDataSource{}
+
+func NewSnapshotsDataSource() datasource.DataSource {
+	return &SnapshotsDataSource{}
+}
+
+type SnapshotsDataSource struct {
+	client *daytona.Client
+}
+
+type SnapshotsDataSourceModel struct {
+	NamePrefix types.String         `tfsdk:"name_prefix"`
+	States     types.List           `tfsdk:"states"`
+	Snapshots  []SnapshotsItemModel `tfsdk:"snapshots"`
+}
+
+type SnapshotsItemModel struct {
+	Id             types.String  `tfsdk:"id"`
+	Name           types.String  `tfsdk:"name"`
+	ImageName      types.String  `tfsdk:"image_name"`
+	State          types.String  `tfsdk:"state"`
+	General        types.Bool    `tfsdk:"general"`
+	Entrypoint     types.List    `tfsdk:"entrypoint"`
+	OrganizationId types.String  `tfsdk:"organization_id"`
+	Size           types.Float32 `tfsdk:"size"`
+	Cpu            types.Int32   `tfsdk:"cpu"`
+	Gpu            types.Int32   `tfsdk:"gpu"`
+	Memory         types.Int32   `tfsdk:"memory"`
+	Disk           types.Int32   `tfsdk:"disk"`
+	CreatedAt      types.String  `tfsdk:"created_at"`
+	LastUsedAt     types.String  `tfsdk:"last_used_at"`
+}
+
+// snapshotsPageSize is the number of snapshots fetched per page.
+const snapshotsPageSize = 100
+
+func (d *SnapshotsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snapshots"
+}
+
+func (d *SnapshotsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	states := make([]string, 0, len(apiclient.AllowedSnapshotStateEnumValues))
+	for _, state := range apiclient.AllowedSnapshotStateEnumValues {
+		states = append(states, string(state))
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the snapshots available to the organization, sorted by name. Daytona snapshots have no labels, so they can only be filtered by name and state",
+
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				MarkdownDescription: "Only list snapshots whose name starts with this prefix",
+				Optional:            true,
+			},
+			"states": schema.ListAttribute{
+				MarkdownDescription: "Only list snapshots in one of these states, e.g. `active`",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(states...)),
+				},
+			},
+			"snapshots": schema.ListNestedAttribute{
+				MarkdownDescription: "The matching snapshots",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the snapshot",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the snapshot",
+							Computed:            true,
+						},
+						"image_name": schema.StringAttribute{
+							MarkdownDescription: "The container image name for the snapshot",
+							Computed:            true,
+						},
+						"state": schema.StringAttribute{
+							MarkdownDescription: "The state of the snapshot",
+							Computed:            true,
+						},
+						"general": schema.BoolAttribute{
+							MarkdownDescription: "Whether the snapshot is a general snapshot provided by Daytona to all organizations",
+							Computed:            true,
+						},
+						"entrypoint": schema.ListAttribute{
+							MarkdownDescription: "The entrypoint command for the snapshot",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"organization_id": schema.StringAttribute{
+							MarkdownDescription: "The organization ID for the snapshot",
+							Computed:            true,
+						},
+						"size": schema.Float32Attribute{
+							MarkdownDescription: "The size of the snapshot in bytes",
+							Computed:            true,
+						},
+						"cpu": schema.Int32Attribute{
+							MarkdownDescription: "CPU cores allocated to the resulting sandbox",
+							Computed:            true,
+						},
+						"gpu": schema.Int32Attribute{
+							MarkdownDescription: "GPU units allocated to the resulting sandbox",
+							Computed:            true,
+						},
+						"memory": schema.Int32Attribute{
+							MarkdownDescription: "Memory allocated to the resulting sandbox in GB",
+							Computed:            true,
+						},
+						"disk": schema.Int32Attribute{
+							MarkdownDescription: "Disk space allocated to the resulting sandbox in GB",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "The creation timestamp of the snapshot",
+							Computed:            true,
+						},
+						"last_used_at": schema.StringAttribute{
+							MarkdownDescription: "When a sandbox was last created from the snapshot, null if it never was",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *SnapshotsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *SnapshotsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SnapshotsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var states []string
+	if !data.States.IsNull() {
+		resp.Diagnostics.Append(data.States.ElementsAs(ctx, &states, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	snapshots, err := listSnapshots(ctx, d.client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to list snapshots, got error: %s", err),
+		)
+		return
+	}
+
+	slices.SortFunc(snapshots, func(a, b apiclient.SnapshotDto) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	data.Snapshots = []SnapshotsItemModel{}
+	for _, snapshot := range snapshots {
+		if !strings.HasPrefix(snapshot.Name, data.NamePrefix.ValueString()) {
+			continue
+		}
+		if len(states) > 0 && !slices.Contains(states, string(snapshot.State)) {
+			continue
+		}
+
+		item, diags := snapshotsItem(ctx, &snapshot)
+		resp.Diagnostics.Append(diags...)
+		data.Snapshots = append(data.Snapshots, item)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func snapshotsItem(ctx context.Context, snapshot *apiclient.SnapshotDto) (item SnapshotsItemModel, diags diag.Diagnostics) {
+	item = SnapshotsItemModel{
+		Id:             types.StringValue(snapshot.Id),
+		Name:           types.StringValue(snapshot.Name),
+		ImageName:      types.StringPointerValue(snapshot.ImageName),
+		State:          types.StringValue(string(snapshot.State)),
+		General:        types.BoolValue(snapshot.General),
+		OrganizationId: types.StringPointerValue(snapshot.OrganizationId),
+		Size:           types.Float32PointerValue(snapshot.Size.Get()),
+		Cpu:            types.Int32Value(int32(snapshot.Cpu)),
+		Gpu:            types.Int32Value(int32(snapshot.Gpu)),
+		Memory:         types.Int32Value(int32(snapshot.Mem)),
+		Disk:           types.Int32Value(int32(snapshot.Disk)),
+		CreatedAt:      types.StringValue(snapshot.CreatedAt.Format("2006-01-02T15:04:05Z07:00")),
+		LastUsedAt:     types.StringNull(),
+	}
+
+	if lastUsedAt := snapshot.LastUsedAt.Get(); lastUsedAt != nil {
+		item.LastUsedAt = types.StringValue(lastUsedAt.Format("2006-01-02T15:04:05Z07:00"))
+	}
+
+	item.Entrypoint, diags = types.ListValueFrom(ctx, types.StringType, snapshot.Entrypoint)
+	return
+}
+
+// listSnapshots fetches all snapshots, one page at a time.
+func listSnapshots(ctx context.Context, client *daytona.Client) ([]apiclient.SnapshotDto, error) {
+	var snapshots []apiclient.SnapshotDto
+
+	for page := 1; ; page++ {
+		paginated, httpResp, err := client.SnapshotsAPI.GetAllSnapshots(ctx).Page(float32(page)).Limit(snapshotsPageSize).Execute()
+		if httpResp != nil && httpResp.Body != nil {
+			httpResp.Body.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, paginated.Items...)
+		if float32(page) >= paginated.TotalPages {
+			return snapshots, nil
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package daytona
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tokenRefreshMargin is how long before its expiry a refreshable token is
+// replaced, so it doesn't expire while a request is in flight.
+const tokenRefreshMargin = time.Minute
+
+// Credential is an API token along with its expiry, if known.
+type Credential struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TokenSource holds the API token of a provider instance. Tokens read from a
+// file or a credential command can be refreshed, which replaces them shortly
+// before they expire and whenever the API rejects them.
+type TokenSource struct {
+	refresh func(ctx context.Context) (Credential, error)
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewTokenSource creates a token source for the credential. refresh fetches
+// a new one and may be nil for tokens that can't be refreshed. Credentials
+// without an expiry fall back to the exp claim of JWT tokens.
+func NewTokenSource(credential Credential, refresh func(ctx context.Context) (Credential, error)) *TokenSource {
+	s := &TokenSource{
+		refresh: refresh,
+	}
+	s.set(credential)
+	return s
+}
+
+// Token returns the current token, refreshing it first if it's about to
+// expire.
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.refresh != nil && !s.expiresAt.IsZero() && time.Until(s.expiresAt) < tokenRefreshMargin {
+		if err := s.refreshLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+	return s.token, nil
+}
+
+// Refresh replaces the token after the API rejected it. Requests that failed
+// concurrently with the same token share a single refresh.
+func (s *TokenSource) Refresh(ctx context.Context, rejected string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == rejected {
+		if err := s.refreshLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+	return s.token, nil
+}
+
+// Refreshable returns whether the token can be refreshed.
+func (s *TokenSource) Refreshable() bool {
+	return s.refresh != nil
+}
+
+// ExpiresAt returns when the current token expires, or the zero time if
+// that's unknown.
+func (s *TokenSource) ExpiresAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.expiresAt
+}
+
+func (s *TokenSource) refreshLocked(ctx context.Context) error {
+	credential, err := s.refresh(ctx)
+	if err != nil {
+		return err
+	}
+	s.set(credential)
+
+	tflog.Debug(ctx, "Refreshed API token", map[string]any{
+		"expires_at": s.expiresAt.Format(time.RFC3339),
+	})
+	return nil
+}
+
+func (s *TokenSource) set(credential Credential) {
+	s.token = credential.Token
+	s.expiresAt = credential.ExpiresAt
+	if s.expiresAt.IsZero() {
+		s.expiresAt, _ = TokenExpiry(credential.Token)
+	}
+}
+
+// TokenExpiry returns the expiry of a JWT token from its exp claim. The
+// token isn't verified, that's up to the API. Tokens that aren't JWTs, like
+// API keys, have no known expiry.
+func TokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp *float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(*claims.Exp), 0), true
+}
+
+// AuthTransport authenticates API requests with the token of a TokenSource.
+// Requests rejected with 401 are sent once more with a refreshed token if the
+// token can be refreshed.
+type AuthTransport struct {
+	next   http.RoundTripper
+	source *TokenSource
+}
+
+func NewAuthTransport(next http.RoundTripper, source *TokenSource) *AuthTransport {
+	return &AuthTransport{
+		next:   next,
+		source: source,
+	}
+}
+
+func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	token, err := t.source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(authenticated(req, token))
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !t.source.Refreshable() || !replayable {
+		return resp, err
+	}
+
+	// drain the body so the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	tflog.Debug(ctx, "API token was rejected, refreshing it", map[string]any{
+		"method": req.Method,
+		"path":   req.URL.Path,
+	})
+
+	token, err = t.source.Refresh(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	retry := authenticated(req, token)
+	if req.Body != nil && req.Body != http.NoBody {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(retry)
+}
+
+// authenticated returns a copy of the request carrying the token.
+func authenticated(req *http.Request, token string) *http.Request {
+	authenticated := req.Clone(req.Context())
+	authenticated.Header.Set("Authorization", "Bearer "+token)
+	return authenticated
+}
@@ -0,0 +1,183 @@
+package daytona
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tokenServer accepts requests with the valid token and answers them with
+// the body they were sent.
+func tokenServer(t *testing.T, valid *string, mu *sync.Mutex) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		token := *valid
+		mu.Unlock()
+
+		if req.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func jwtExpiringAt(expiresAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, expiresAt.Unix())))
+	return "header." + payload + ".signature"
+}
+
+func TestAuthTransportRefreshesRejectedToken(t *testing.T) {
+	var mu sync.Mutex
+	valid := "new"
+	server := tokenServer(t, &valid, &mu)
+
+	refreshes := 0
+	source := NewTokenSource(Credential{Token: "old"}, func(ctx context.Context) (Credential, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		refreshes++
+		return Credential{Token: "new"}, nil
+	})
+	transport := NewAuthTransport(http.DefaultTransport, source)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("body"))
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := readBody(t, resp); resp.StatusCode != http.StatusOK || body != "body" {
+		t.Errorf("expected the request to be sent again with its body, got %d %q", resp.StatusCode, body)
+	}
+	if refreshes != 1 {
+		t.Errorf("expected 1 refresh, got %d", refreshes)
+	}
+
+	// the refreshed token is used right away
+	req, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err = transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || refreshes != 1 {
+		t.Errorf("expected the refreshed token to be kept, got %d after %d refreshes", resp.StatusCode, refreshes)
+	}
+}
+
+func TestAuthTransportSharesRefreshes(t *testing.T) {
+	var mu sync.Mutex
+	valid := "new"
+	server := tokenServer(t, &valid, &mu)
+
+	refreshes := 0
+	source := NewTokenSource(Credential{Token: "old"}, func(ctx context.Context) (Credential, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		refreshes++
+		return Credential{Token: "new"}, nil
+	})
+	transport := NewAuthTransport(http.DefaultTransport, source)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("expected 200, got %d", resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if refreshes != 1 {
+		t.Errorf("expected requests rejected with the same token to share 1 refresh, got %d", refreshes)
+	}
+}
+
+func TestAuthTransportWithoutRefresh(t *testing.T) {
+	var mu sync.Mutex
+	valid := "other"
+	server := tokenServer(t, &valid, &mu)
+	transport := NewAuthTransport(http.DefaultTransport, NewTokenSource(Credential{Token: "token"}, nil))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected the 401 to be returned, got %d", resp.StatusCode)
+	}
+}
+
+func TestTokenSourceRefreshesExpiringToken(t *testing.T) {
+	expiring := jwtExpiringAt(time.Now().Add(tokenRefreshMargin / 2))
+	fresh := jwtExpiringAt(time.Now().Add(time.Hour))
+
+	source := NewTokenSource(Credential{Token: expiring}, func(ctx context.Context) (Credential, error) {
+		return Credential{Token: fresh}, nil
+	})
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != fresh {
+		t.Errorf("expected the token about to expire to be refreshed")
+	}
+
+	failing := NewTokenSource(Credential{Token: expiring}, func(ctx context.Context) (Credential, error) {
+		return Credential{}, errors.New("credential command failed")
+	})
+	if _, err := failing.Token(context.Background()); err == nil || err.Error() != "credential command failed" {
+		t.Errorf("expected the refresh error, got %v", err)
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	expiresAt := time.Unix(1767225600, 0)
+
+	tests := map[string]struct {
+		token    string
+		expected time.Time
+		ok       bool
+	}{
+		"jwt":            {token: jwtExpiringAt(expiresAt), expected: expiresAt, ok: true},
+		"padded payload": {token: "header." + base64.URLEncoding.EncodeToString([]byte(`{"exp":1767225600}`)) + ".signature", expected: expiresAt, ok: true},
+		"api key":        {token: "dtn_0123456789abcdef"},
+		"no exp claim":   {token: "header." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user"}`)) + ".signature"},
+		"invalid base64": {token: "header.!!!.signature"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			expiry, ok := TokenExpiry(test.token)
+			if ok != test.ok || !expiry.Equal(test.expected) {
+				t.Errorf("expected %s, %t, got %s, %t", test.expected, test.ok, expiry, ok)
+			}
+		})
+	}
+}
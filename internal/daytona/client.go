@@ -0,0 +1,42 @@
+package daytona
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+)
+
+// Client is handed to resources and data sources as provider data. It wraps
+// the generated API client together with the settings resolved in the
+// provider configuration.
+type Client struct {
+	*apiclient.APIClient
+
+	OrganizationID string
+	// OperationTimeout bounds waits for long-running operations, like a
+	// snapshot being processed. Zero means no limit.
+	OperationTimeout time.Duration
+	// Docker selects the Docker daemon that builds and pushes images
+	Docker DockerConfig
+	// PushParallelism is how many layers direct pushes upload at a time. Zero
+	// means the default.
+	PushParallelism int
+	// HTTPClient talks to container registries through the configured proxy
+	// and TLS settings. Unlike the API client, it doesn't send the API token,
+	// registries are authenticated separately.
+	HTTPClient *http.Client
+
+	pushAccess pushAccessCache
+}
+
+// DockerConfig selects a Docker daemon. Anything left empty is taken from
+// the environment, like DOCKER_HOST, the way the Docker CLI does without a
+// context.
+type DockerConfig struct {
+	// Host is the daemon's address, e.g. tcp://docker.internal:2376
+	Host string
+	// TLS secures the connection to a tcp:// host
+	TLS *tls.Config
+}
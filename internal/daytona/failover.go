@@ -0,0 +1,118 @@
+package daytona
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// FailoverTransport spreads API requests over a list of equivalent endpoints,
+// e.g. several ingress points of a self-hosted deployment. Requests are
+// addressed to the first endpoint and moved to the next one whenever an
+// endpoint can't be reached. Requests that aren't idempotent are only moved
+// when they weren't sent, as a server that timed out may still process them.
+// The endpoint that answered last is tried first for later requests.
+type FailoverTransport struct {
+	next      http.RoundTripper
+	endpoints []*url.URL
+
+	mu      sync.Mutex
+	current int
+}
+
+func NewFailoverTransport(next http.RoundTripper, endpoints []*url.URL) *FailoverTransport {
+	return &FailoverTransport{
+		next:      next,
+		endpoints: endpoints,
+	}
+}
+
+func (t *FailoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	primary := t.endpoints[0]
+	suffix, ok := cutPathPrefix(req.URL.Path, primary.Path)
+	if !ok || req.URL.Host != primary.Host {
+		return t.next.RoundTrip(req)
+	}
+
+	t.mu.Lock()
+	start := t.current
+	t.mu.Unlock()
+
+	idempotent := idempotentMethod(req.Method)
+
+	var lastErr error
+	for i := range t.endpoints {
+		index := (start + i) % len(t.endpoints)
+		endpoint := t.endpoints[index]
+
+		attempt := req.Clone(req.Context())
+		attempt.URL.Scheme = endpoint.Scheme
+		attempt.URL.Host = endpoint.Host
+		attempt.URL.Path = strings.TrimSuffix(endpoint.Path, "/") + suffix
+		attempt.URL.RawPath = ""
+		attempt.Host = endpoint.Host
+
+		// the original body was consumed by the failed attempt
+		if i > 0 && req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return nil, lastErr
+			}
+
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(attempt)
+		if err == nil {
+			t.mu.Lock()
+			t.current = index
+			t.mu.Unlock()
+
+			return resp, nil
+		}
+
+		// a cancelled request would fail on every endpoint
+		if req.Context().Err() != nil || !idempotent && !unsent(err) {
+			return nil, err
+		}
+
+		tflog.Warn(req.Context(), "API endpoint unreachable, failing over", map[string]any{
+			"endpoint": endpoint.String(),
+			"error":    err.Error(),
+		})
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+// unsent reports whether a request failed before it was sent, as the
+// endpoint couldn't be resolved or connected to.
+func unsent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// cutPathPrefix removes the path prefix ending at a segment boundary, so
+// /api matches /api and /api/snapshots but not /apix.
+func cutPathPrefix(path, prefix string) (string, bool) {
+	suffix, ok := strings.CutPrefix(path, strings.TrimSuffix(prefix, "/"))
+	if !ok || suffix != "" && !strings.HasPrefix(suffix, "/") {
+		return "", false
+	}
+	return suffix, true
+}
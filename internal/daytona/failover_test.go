@@ -0,0 +1,158 @@
+package daytona
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// unreachableURL returns the URL of a server that has been shut down, so
+// connections to it are refused.
+func unreachableURL(t *testing.T, path string) *url.URL {
+	t.Helper()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	return mustParseURL(t, server.URL+path)
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+// echoServer answers every request with its path and body.
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		io.WriteString(w, req.URL.Path+" "+string(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestFailoverTransport(t *testing.T) {
+	fallback := echoServer(t)
+	primary := unreachableURL(t, "/api")
+	transport := NewFailoverTransport(http.DefaultTransport, []*url.URL{primary, mustParseURL(t, fallback.URL+"/v1/")})
+
+	req, _ := http.NewRequest(http.MethodPost, primary.String()+"/snapshots", strings.NewReader("body"))
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := readBody(t, resp); body != "/v1/snapshots body" {
+		t.Errorf("expected the request and its body on the fallback, got %q", body)
+	}
+
+	// the endpoint that answered is tried first from now on
+	req, _ = http.NewRequest(http.MethodGet, primary.String(), nil)
+	resp, err = transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := readBody(t, resp); body != "/v1 " {
+		t.Errorf("expected the API root on the fallback, got %q", body)
+	}
+}
+
+func TestFailoverTransportMatchesPathSegments(t *testing.T) {
+	fallback := echoServer(t)
+	primary := unreachableURL(t, "/api")
+	transport := NewFailoverTransport(http.DefaultTransport, []*url.URL{primary, mustParseURL(t, fallback.URL+"/v1")})
+
+	tests := map[string]struct {
+		path     string
+		expected string
+	}{
+		"endpoint":       {path: "/api", expected: "/v1"},
+		"below endpoint": {path: "/api/snapshots", expected: "/v1/snapshots"},
+		// not below the endpoint, so sent to the primary host as it is
+		"sibling path": {path: "/apix/snapshots"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "http://"+primary.Host+test.path, nil)
+			resp, err := transport.RoundTrip(req)
+			if test.expected == "" {
+				if err == nil {
+					t.Errorf("expected %s to be sent to the primary host, got %q", test.path, readBody(t, resp))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body := readBody(t, resp); body != test.expected+" " {
+				t.Errorf("expected %q to be requested, got %q", test.expected, body)
+			}
+		})
+	}
+}
+
+func TestFailoverTransportAllUnreachable(t *testing.T) {
+	primary := unreachableURL(t, "/api")
+	transport := NewFailoverTransport(http.DefaultTransport, []*url.URL{primary, unreachableURL(t, "/api")})
+
+	req, _ := http.NewRequest(http.MethodGet, primary.String()+"/snapshots", nil)
+	if _, err := transport.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected the last connection error, got %v", err)
+	}
+}
+
+func TestFailoverTransportTimeout(t *testing.T) {
+	var sent atomic.Int32
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sent.Add(1)
+		<-release
+	}))
+	t.Cleanup(hanging.Close)
+	t.Cleanup(func() { close(release) })
+
+	fallback := echoServer(t)
+	primary := mustParseURL(t, hanging.URL+"/api")
+	transport := NewFailoverTransport(&http.Transport{ResponseHeaderTimeout: 50 * time.Millisecond}, []*url.URL{primary, mustParseURL(t, fallback.URL+"/api")})
+
+	// the hanging server may still create the snapshot, so it isn't created twice
+	req, _ := http.NewRequest(http.MethodPost, primary.String()+"/snapshots", strings.NewReader("body"))
+	if resp, err := transport.RoundTrip(req); err == nil {
+		t.Errorf("expected the timeout, got %q from the fallback", readBody(t, resp))
+	}
+	if sent.Load() != 1 {
+		t.Errorf("expected the request to be sent once, got %d", sent.Load())
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, primary.String()+"/snapshots", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := readBody(t, resp); body != "/api/snapshots " {
+		t.Errorf("expected the idempotent request on the fallback, got %q", body)
+	}
+}
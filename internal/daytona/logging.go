@@ -0,0 +1,72 @@
+package daytona
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// sensitiveHeaderParts mark headers whose values are never logged, like
+// Authorization or the registry credentials of Docker requests.
+var sensitiveHeaderParts = []string{"auth", "cookie", "token", "secret", "key", "credential"}
+
+// LoggingTransport logs every API request attempt with its outcome and
+// headers. Values of headers that may carry credentials are redacted, and
+// bodies are never logged since some of them, like registry push access,
+// contain secrets.
+type LoggingTransport struct {
+	next http.RoundTripper
+}
+
+func NewLoggingTransport(next http.RoundTripper) *LoggingTransport {
+	return &LoggingTransport{
+		next: next,
+	}
+}
+
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+
+	fields := map[string]any{
+		"method":          req.Method,
+		"host":            req.URL.Host,
+		"path":            req.URL.Path,
+		"query":           req.URL.RawQuery,
+		"duration":        time.Since(start).String(),
+		"request_headers": redactHeaders(req.Header),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Info(ctx, "API request failed", fields)
+		return resp, err
+	}
+
+	fields["status"] = resp.StatusCode
+	fields["response_headers"] = redactHeaders(resp.Header)
+	tflog.Info(ctx, "API request", fields)
+	return resp, nil
+}
+
+// redactHeaders flattens the headers for logging, replacing values of
+// sensitive ones.
+func redactHeaders(header http.Header) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+
+		lower := strings.ToLower(name)
+		for _, part := range sensitiveHeaderParts {
+			if strings.Contains(lower, part) {
+				value = "<redacted>"
+				break
+			}
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
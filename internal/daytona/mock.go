@@ -0,0 +1,543 @@
+package daytona
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+)
+
+// MockOrganizationID is the organization ID used in mock mode when the
+// provider configuration doesn't set one.
+const MockOrganizationID = "00000000-0000-0000-0000-000000000000"
+
+var mockTimestamp = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// MockTransport answers API requests with deterministic fake data instead of
+// talking to Daytona. Objects created through the transport are remembered for
+// the lifetime of the provider instance so that create/read/delete cycles
+// behave consistently.
+type MockTransport struct {
+	basePath         string
+	organizationID   string
+	organizationName string
+
+	mu         sync.Mutex
+	snapshots  map[string]*apiclient.SnapshotDto
+	deleted    map[string]bool
+	members    map[string]*apiclient.OrganizationUser
+	registries map[string]*apiclient.DockerRegistry
+	files      map[string][]byte
+	quota      apiclient.UpdateOrganizationQuota
+	roles      map[string]*apiclient.OrganizationRole
+}
+
+func NewMockTransport(basePath, organizationID, organizationName string) *MockTransport {
+	if organizationName == "" {
+		organizationName = "mock"
+	}
+
+	return &MockTransport{
+		basePath:         strings.TrimSuffix(basePath, "/"),
+		organizationID:   organizationID,
+		organizationName: organizationName,
+		snapshots:        map[string]*apiclient.SnapshotDto{},
+		deleted:          map[string]bool{},
+		members:          map[string]*apiclient.OrganizationUser{},
+		registries:       map[string]*apiclient.DockerRegistry{},
+		files:            map[string][]byte{},
+		roles:            map[string]*apiclient.OrganizationRole{},
+	}
+}
+
+func (t *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+
+	path := strings.Trim(strings.TrimPrefix(req.URL.Path, t.basePath), "/")
+	segments := strings.Split(path, "/")
+
+	switch {
+	case req.Method == http.MethodGet && path == "organizations":
+		return t.respond(req, http.StatusOK, []apiclient.Organization{t.organization()})
+	case req.Method == http.MethodGet && len(segments) == 2 && segments[0] == "organizations":
+		return t.respond(req, http.StatusOK, t.organization())
+	case req.Method == http.MethodGet && len(segments) == 3 && segments[0] == "organizations" && segments[2] == "roles":
+		roles := []apiclient.OrganizationRole{}
+		for _, role := range t.roles {
+			roles = append(roles, *role)
+		}
+		return t.respond(req, http.StatusOK, roles)
+	case req.Method == http.MethodPost && len(segments) == 3 && segments[0] == "organizations" && segments[2] == "roles":
+		var create apiclient.CreateOrganizationRole
+		if err := json.NewDecoder(req.Body).Decode(&create); err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		role := &apiclient.OrganizationRole{
+			Id:          mockID("role", create.Name),
+			Name:        create.Name,
+			Description: create.Description,
+			Permissions: create.Permissions,
+			CreatedAt:   mockTimestamp,
+			UpdatedAt:   mockTimestamp,
+		}
+		t.roles[role.Id] = role
+		return t.respond(req, http.StatusOK, role)
+	case req.Method == http.MethodPut && len(segments) == 4 && segments[0] == "organizations" && segments[2] == "roles":
+		role, ok := t.roles[segments[3]]
+		if !ok {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "role not found"})
+		}
+		var update apiclient.UpdateOrganizationRole
+		if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		role.Name = update.Name
+		role.Description = update.Description
+		role.Permissions = update.Permissions
+		return t.respond(req, http.StatusOK, role)
+	case req.Method == http.MethodDelete && len(segments) == 4 && segments[0] == "organizations" && segments[2] == "roles":
+		if _, ok := t.roles[segments[3]]; !ok {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "role not found"})
+		}
+		delete(t.roles, segments[3])
+		return t.respond(req, http.StatusOK, nil)
+	case req.Method == http.MethodPatch && len(segments) == 3 && segments[0] == "organizations" && segments[2] == "quota":
+		var update apiclient.UpdateOrganizationQuota
+		if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		for _, field := range []struct{ current, updated *apiclient.NullableFloat32 }{
+			{&t.quota.TotalCpuQuota, &update.TotalCpuQuota},
+			{&t.quota.TotalMemoryQuota, &update.TotalMemoryQuota},
+			{&t.quota.TotalDiskQuota, &update.TotalDiskQuota},
+			{&t.quota.MaxCpuPerSandbox, &update.MaxCpuPerSandbox},
+			{&t.quota.MaxMemoryPerSandbox, &update.MaxMemoryPerSandbox},
+			{&t.quota.MaxDiskPerSandbox, &update.MaxDiskPerSandbox},
+		} {
+			if field.updated.Get() != nil {
+				field.current.Set(field.updated.Get())
+			}
+		}
+		return t.respond(req, http.StatusOK, t.organization())
+	case req.Method == http.MethodGet && len(segments) == 3 && segments[0] == "organizations" && segments[2] == "usage":
+		return t.respond(req, http.StatusOK, apiclient.UsageOverview{
+			TotalCpuQuota:    100,
+			TotalGpuQuota:    0,
+			TotalMemoryQuota: 200,
+			TotalDiskQuota:   500,
+		})
+	case req.Method == http.MethodGet && len(segments) == 3 && segments[0] == "organizations" && segments[2] == "users":
+		members := []apiclient.OrganizationUser{}
+		for _, member := range t.members {
+			members = append(members, *member)
+		}
+		return t.respond(req, http.StatusOK, members)
+	case req.Method == http.MethodPost && len(segments) == 5 && segments[0] == "organizations" && segments[2] == "users" && segments[4] == "role":
+		var update apiclient.UpdateOrganizationMemberRole
+		if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		member := t.member(segments[3])
+		member.Role = update.Role
+		return t.respond(req, http.StatusOK, member)
+	case req.Method == http.MethodPost && len(segments) == 5 && segments[0] == "organizations" && segments[2] == "users" && segments[4] == "assigned-roles":
+		var update apiclient.UpdateAssignedOrganizationRoles
+		if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		member := t.member(segments[3])
+		member.AssignedRoles = []apiclient.OrganizationRole{}
+		for _, roleID := range update.RoleIds {
+			if role, ok := t.roles[roleID]; ok {
+				member.AssignedRoles = append(member.AssignedRoles, *role)
+				continue
+			}
+			member.AssignedRoles = append(member.AssignedRoles, apiclient.OrganizationRole{
+				Id:          roleID,
+				Name:        roleID,
+				Permissions: []string{},
+				CreatedAt:   mockTimestamp,
+				UpdatedAt:   mockTimestamp,
+			})
+		}
+		return t.respond(req, http.StatusOK, member)
+	case req.Method == http.MethodGet && path == "docker-registry/registry-push-access":
+		return t.respond(req, http.StatusOK, apiclient.RegistryPushAccessDto{
+			Username:    "mock",
+			Secret:      "mock",
+			RegistryUrl: "registry.mock.daytona.invalid",
+			RegistryId:  mockID("registry", "transient"),
+			Project:     "mock",
+			ExpiresAt:   mockTimestamp.Add(time.Hour).Format(time.RFC3339),
+		})
+	case req.Method == http.MethodGet && path == "docker-registry":
+		registries := []apiclient.DockerRegistry{}
+		for _, registry := range t.registries {
+			registries = append(registries, *registry)
+		}
+		return t.respond(req, http.StatusOK, registries)
+	case req.Method == http.MethodPost && path == "docker-registry":
+		var create apiclient.CreateDockerRegistry
+		if err := json.NewDecoder(req.Body).Decode(&create); err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		registry := &apiclient.DockerRegistry{
+			Id:           mockID("registry", create.Name),
+			Name:         create.Name,
+			Url:          create.Url,
+			Username:     create.Username,
+			Project:      create.GetProject(),
+			RegistryType: create.RegistryType,
+			CreatedAt:    mockTimestamp,
+			UpdatedAt:    mockTimestamp,
+		}
+		t.registries[registry.Id] = registry
+		return t.respond(req, http.StatusOK, registry)
+	case req.Method == http.MethodGet && len(segments) == 2 && segments[0] == "docker-registry":
+		registry, ok := t.registries[segments[1]]
+		if !ok {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "registry not found"})
+		}
+		return t.respond(req, http.StatusOK, registry)
+	case req.Method == http.MethodPatch && len(segments) == 2 && segments[0] == "docker-registry":
+		registry, ok := t.registries[segments[1]]
+		if !ok {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "registry not found"})
+		}
+		var update apiclient.UpdateDockerRegistry
+		if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		registry.Name = update.Name
+		registry.Url = update.Url
+		registry.Username = update.Username
+		registry.Project = update.GetProject()
+		return t.respond(req, http.StatusOK, registry)
+	case req.Method == http.MethodDelete && len(segments) == 2 && segments[0] == "docker-registry":
+		if _, ok := t.registries[segments[1]]; !ok {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "registry not found"})
+		}
+		delete(t.registries, segments[1])
+		return t.respond(req, http.StatusOK, nil)
+	case req.Method == http.MethodGet && path == "runners":
+		return t.respond(req, http.StatusOK, []apiclient.Runner{t.runner()})
+	case req.Method == http.MethodGet && path == "snapshots":
+		items := []apiclient.SnapshotDto{t.generalSnapshot()}
+		for id, snapshot := range t.snapshots {
+			if !t.deleted[id] {
+				items = append(items, *snapshot)
+			}
+		}
+		return t.respond(req, http.StatusOK, apiclient.PaginatedSnapshotsDto{
+			Items:      items,
+			Total:      float32(len(items)),
+			Page:       1,
+			TotalPages: 1,
+		})
+	case req.Method == http.MethodPost && path == "snapshots":
+		var create apiclient.CreateSnapshot
+		if err := json.NewDecoder(req.Body).Decode(&create); err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		snapshot := t.snapshot(create.Name)
+		snapshot.ImageName = create.ImageName
+		if create.Entrypoint != nil {
+			snapshot.Entrypoint = create.Entrypoint
+		}
+		if create.Cpu != nil {
+			snapshot.Cpu = float32(*create.Cpu)
+		}
+		if create.Memory != nil {
+			snapshot.Mem = float32(*create.Memory)
+		}
+		if create.Disk != nil {
+			snapshot.Disk = float32(*create.Disk)
+		}
+		delete(t.deleted, snapshot.Id)
+		t.snapshots[snapshot.Id] = snapshot
+		return t.respond(req, http.StatusOK, snapshot)
+	case req.Method == http.MethodGet && len(segments) == 2 && segments[0] == "snapshots":
+		snapshot := t.lookupSnapshot(segments[1])
+		if snapshot == nil {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "snapshot not found"})
+		}
+		return t.respond(req, http.StatusOK, snapshot)
+	case req.Method == http.MethodDelete && len(segments) == 2 && segments[0] == "snapshots":
+		snapshot := t.lookupSnapshot(segments[1])
+		if snapshot == nil {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "snapshot not found"})
+		}
+		t.deleted[snapshot.Id] = true
+		return t.respond(req, http.StatusOK, nil)
+	case req.Method == http.MethodPost && len(segments) == 3 && segments[0] == "snapshots" && segments[2] == "deactivate":
+		snapshot := t.lookupSnapshot(segments[1])
+		if snapshot == nil {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "snapshot not found"})
+		}
+		snapshot.State = apiclient.SNAPSHOTSTATE_INACTIVE
+		return t.respond(req, http.StatusOK, nil)
+	case req.Method == http.MethodPost && path == "api-keys":
+		var create apiclient.CreateApiKey
+		if err := json.NewDecoder(req.Body).Decode(&create); err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		return t.respond(req, http.StatusOK, apiclient.ApiKeyResponse{
+			Name:        create.Name,
+			Value:       "mock-" + mockID("api-key", create.Name),
+			CreatedAt:   mockTimestamp,
+			Permissions: create.Permissions,
+			ExpiresAt:   create.ExpiresAt,
+		})
+	case req.Method == http.MethodDelete && len(segments) == 2 && segments[0] == "api-keys":
+		return t.respond(req, http.StatusOK, nil)
+	case req.Method == http.MethodGet && path == "sandbox":
+		return t.respond(req, http.StatusOK, []apiclient.Sandbox{})
+	case req.Method == http.MethodGet && len(segments) == 2 && segments[0] == "sandbox":
+		return t.respond(req, http.StatusOK, t.sandbox(segments[1]))
+	case req.Method == http.MethodPost && len(segments) == 5 && segments[0] == "toolbox" && segments[2] == "toolbox" && segments[3] == "process" && segments[4] == "execute":
+		var execute apiclient.ExecuteRequest
+		if err := json.NewDecoder(req.Body).Decode(&execute); err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		return t.respond(req, http.StatusOK, apiclient.ExecuteResponse{
+			ExitCode: 0,
+			Result:   "",
+		})
+	case req.Method == http.MethodPost && len(segments) == 5 && segments[0] == "toolbox" && segments[3] == "files" && segments[4] == "upload":
+		file, _, err := req.FormFile("file")
+		if err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return t.respond(req, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
+		t.files[segments[1]+":"+req.URL.Query().Get("path")] = content
+		return t.respond(req, http.StatusOK, nil)
+	case req.Method == http.MethodGet && len(segments) == 5 && segments[0] == "toolbox" && segments[3] == "files" && segments[4] == "download":
+		content, ok := t.files[segments[1]+":"+req.URL.Query().Get("path")]
+		if !ok {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "file not found"})
+		}
+		return t.respondRaw(req, http.StatusOK, "application/octet-stream", content)
+	case req.Method == http.MethodPost && len(segments) == 5 && segments[0] == "toolbox" && segments[3] == "files" && segments[4] == "permissions":
+		if _, ok := t.files[segments[1]+":"+req.URL.Query().Get("path")]; !ok {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "file not found"})
+		}
+		return t.respond(req, http.StatusOK, nil)
+	case req.Method == http.MethodDelete && len(segments) == 4 && segments[0] == "toolbox" && segments[3] == "files":
+		key := segments[1] + ":" + req.URL.Query().Get("path")
+		if _, ok := t.files[key]; !ok {
+			return t.respond(req, http.StatusNotFound, map[string]string{"message": "file not found"})
+		}
+		delete(t.files, key)
+		return t.respond(req, http.StatusOK, nil)
+	case req.Method == http.MethodGet && len(segments) == 5 && segments[0] == "sandbox" && segments[2] == "ports" && segments[4] == "preview-url":
+		return t.respond(req, http.StatusOK, apiclient.PortPreviewUrl{
+			Url:   fmt.Sprintf("https://%s-%s.proxy.mock.daytona.invalid", segments[3], segments[1]),
+			Token: "mock-" + mockID("preview-token", segments[1]+"/"+segments[3]),
+		})
+	case req.Method == http.MethodPost && len(segments) == 4 && segments[0] == "sandbox" && segments[2] == "public":
+		return t.respond(req, http.StatusOK, nil)
+	}
+
+	return t.respond(req, http.StatusNotImplemented, map[string]string{
+		"message": fmt.Sprintf("%s /%s is not supported in mock mode", req.Method, path),
+	})
+}
+
+func (t *MockTransport) organization() apiclient.Organization {
+	organization := apiclient.Organization{
+		Id:                  t.organizationID,
+		Name:                t.organizationName,
+		CreatedBy:           mockID("user", "mock"),
+		CreatedAt:           mockTimestamp,
+		UpdatedAt:           mockTimestamp,
+		TotalCpuQuota:       100,
+		TotalMemoryQuota:    200,
+		TotalDiskQuota:      500,
+		MaxCpuPerSandbox:    4,
+		MaxMemoryPerSandbox: 8,
+		MaxDiskPerSandbox:   10,
+	}
+
+	// quotas updated through the transport override the defaults
+	for _, field := range []struct {
+		value    *float32
+		override apiclient.NullableFloat32
+	}{
+		{&organization.TotalCpuQuota, t.quota.TotalCpuQuota},
+		{&organization.TotalMemoryQuota, t.quota.TotalMemoryQuota},
+		{&organization.TotalDiskQuota, t.quota.TotalDiskQuota},
+		{&organization.MaxCpuPerSandbox, t.quota.MaxCpuPerSandbox},
+		{&organization.MaxMemoryPerSandbox, t.quota.MaxMemoryPerSandbox},
+		{&organization.MaxDiskPerSandbox, t.quota.MaxDiskPerSandbox},
+	} {
+		if field.override.Get() != nil {
+			*field.value = *field.override.Get()
+		}
+	}
+
+	return organization
+}
+
+func (t *MockTransport) runner() apiclient.Runner {
+	return apiclient.Runner{
+		Id:        mockID("runner", "default"),
+		Domain:    "runner.mock.daytona.invalid",
+		ApiUrl:    "https://runner.mock.daytona.invalid",
+		ProxyUrl:  "https://proxy.mock.daytona.invalid",
+		Cpu:       16,
+		Memory:    64,
+		Disk:      500,
+		Class:     apiclient.SANDBOXCLASS_SMALL,
+		Capacity:  100,
+		Region:    string(apiclient.RUNNERREGION_US),
+		State:     apiclient.RUNNERSTATE_READY,
+		CreatedAt: mockTimestamp.Format(time.RFC3339),
+		UpdatedAt: mockTimestamp.Format(time.RFC3339),
+		Version:   "mock",
+	}
+}
+
+// generalSnapshot is the default snapshot Daytona provides to all
+// organizations.
+func (t *MockTransport) generalSnapshot() apiclient.SnapshotDto {
+	imageName := "daytonaio/sandbox:mock"
+	return apiclient.SnapshotDto{
+		Id:         mockID("snapshot", "daytona-small"),
+		General:    true,
+		Name:       "daytona-small",
+		ImageName:  &imageName,
+		State:      apiclient.SNAPSHOTSTATE_ACTIVE,
+		Entrypoint: []string{},
+		Cpu:        1,
+		Mem:        1,
+		Disk:       3,
+		CreatedAt:  mockTimestamp,
+		UpdatedAt:  mockTimestamp,
+	}
+}
+
+// member returns the organization member with the given user ID. Members
+// are synthesized the first time they are referenced, so role assignments
+// for any user ID work.
+func (t *MockTransport) member(userID string) *apiclient.OrganizationUser {
+	if member, ok := t.members[userID]; ok {
+		return member
+	}
+
+	member := &apiclient.OrganizationUser{
+		UserId:         userID,
+		OrganizationId: t.organizationID,
+		Name:           userID,
+		Email:          userID + "@mock.daytona.invalid",
+		Role:           "member",
+		AssignedRoles:  []apiclient.OrganizationRole{},
+		CreatedAt:      mockTimestamp,
+		UpdatedAt:      mockTimestamp,
+	}
+	t.members[userID] = member
+	return member
+}
+
+// sandbox synthesizes a started sandbox for any ID, as the provider doesn't
+// create sandboxes that would have to be remembered.
+func (t *MockTransport) sandbox(id string) *apiclient.Sandbox {
+	sandbox := apiclient.NewSandbox(id, t.organizationID, "daytona", map[string]string{}, map[string]string{}, false, "us", 1, 0, 1, 3)
+	sandbox.SetState(apiclient.SANDBOXSTATE_STARTED)
+	return sandbox
+}
+
+// lookupSnapshot resolves a snapshot by ID or name. Snapshots that weren't
+// created through the transport are synthesized from the name and remembered,
+// so data sources referencing pre-existing snapshots still resolve.
+func (t *MockTransport) lookupSnapshot(idOrName string) *apiclient.SnapshotDto {
+	if snapshot, ok := t.snapshots[idOrName]; ok {
+		if t.deleted[snapshot.Id] {
+			return nil
+		}
+		return snapshot
+	}
+
+	for _, snapshot := range t.snapshots {
+		if snapshot.Name == idOrName && !t.deleted[snapshot.Id] {
+			return snapshot
+		}
+	}
+
+	snapshot := t.snapshot(idOrName)
+	if t.deleted[snapshot.Id] {
+		return nil
+	}
+
+	t.snapshots[snapshot.Id] = snapshot
+	return snapshot
+}
+
+func (t *MockTransport) snapshot(name string) *apiclient.SnapshotDto {
+	imageName := fmt.Sprintf("registry.mock.daytona.invalid/mock/%s:latest", name)
+
+	return &apiclient.SnapshotDto{
+		Id:             mockID("snapshot", name),
+		OrganizationId: &t.organizationID,
+		Name:           name,
+		ImageName:      &imageName,
+		State:          apiclient.SNAPSHOTSTATE_ACTIVE,
+		Size:           *apiclient.NewNullableFloat32(nil),
+		Entrypoint:     []string{},
+		Cpu:            1,
+		Mem:            1,
+		Disk:           3,
+		ErrorReason:    *apiclient.NewNullableString(nil),
+		CreatedAt:      mockTimestamp,
+		UpdatedAt:      mockTimestamp,
+		LastUsedAt:     *apiclient.NewNullableTime(nil),
+	}
+}
+
+func (t *MockTransport) respond(req *http.Request, status int, body any) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t.respondRaw(req, status, "application/json", payload)
+}
+
+func (t *MockTransport) respondRaw(req *http.Request, status int, contentType string, payload []byte) (*http.Response, error) {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{contentType}},
+		Body:          io.NopCloser(bytes.NewReader(payload)),
+		ContentLength: int64(len(payload)),
+		Request:       req,
+	}, nil
+}
+
+// mockID derives a stable UUID-formatted identifier from a kind and a name.
+func mockID(kind, name string) string {
+	sum := sha1.Sum([]byte(kind + "/" + name))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
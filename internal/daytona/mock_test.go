@@ -0,0 +1,60 @@
+package daytona
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/daytonaio/apiclient"
+)
+
+func TestMockTransportSnapshotLifecycle(t *testing.T) {
+	transport := NewMockTransport("/api", MockOrganizationID, "")
+
+	send := func(method, path, body string) *http.Response {
+		t.Helper()
+
+		req, _ := http.NewRequest(method, "https://mock.daytona.invalid/api"+path, strings.NewReader(body))
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := send(http.MethodPost, "/snapshots", `{"name":"app","imageName":"app:1.0","cpu":2}`)
+	var created apiclient.SnapshotDto
+	if err := json.Unmarshal([]byte(readBody(t, resp)), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Id != mockID("snapshot", "app") || created.Cpu != 2 {
+		t.Errorf("unexpected snapshot %+v", created)
+	}
+
+	// snapshots resolve by ID and by name
+	for _, idOrName := range []string{created.Id, "app"} {
+		if resp := send(http.MethodGet, "/snapshots/"+idOrName, ""); resp.StatusCode != http.StatusOK {
+			t.Errorf("expected %s to be found, got %d", idOrName, resp.StatusCode)
+		}
+	}
+
+	if resp := send(http.MethodPost, "/snapshots/app/deactivate", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the snapshot to be deactivated, got %d", resp.StatusCode)
+	}
+	resp = send(http.MethodGet, "/snapshots/app", "")
+	var deactivated apiclient.SnapshotDto
+	if err := json.Unmarshal([]byte(readBody(t, resp)), &deactivated); err != nil {
+		t.Fatal(err)
+	}
+	if deactivated.State != apiclient.SNAPSHOTSTATE_INACTIVE {
+		t.Errorf("expected the deactivated snapshot to be inactive, got %q", deactivated.State)
+	}
+
+	if resp := send(http.MethodDelete, "/snapshots/app", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the snapshot to be deleted, got %d", resp.StatusCode)
+	}
+	if resp := send(http.MethodGet, "/snapshots/"+created.Id, ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected the deleted snapshot not to be found, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,52 @@
+package daytona
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+)
+
+// pushAccessMinValidity is how long cached registry credentials must still be
+// valid to be reused, so they don't expire in the middle of a push.
+const pushAccessMinValidity = 5 * time.Minute
+
+// pushAccessCache keeps the transient registry credentials of a provider
+// instance, so an apply pushing many images requests them only once.
+type pushAccessCache struct {
+	mu         sync.Mutex
+	pushAccess *apiclient.RegistryPushAccessDto
+	expiresAt  time.Time
+}
+
+// TransientPushAccess returns credentials for pushing into Daytona's
+// registry, reusing earlier ones while they are valid.
+func (c *Client) TransientPushAccess(ctx context.Context) (*apiclient.RegistryPushAccessDto, error) {
+	c.pushAccess.mu.Lock()
+	defer c.pushAccess.mu.Unlock()
+
+	if c.pushAccess.pushAccess != nil && time.Until(c.pushAccess.expiresAt) > pushAccessMinValidity {
+		return c.pushAccess.pushAccess, nil
+	}
+
+	pushAccess, httpResp, err := c.DockerRegistryAPI.GetTransientPushAccess(ctx).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// credentials without a parseable expiry are used once and not cached
+	expiresAt, err := time.Parse(time.RFC3339, pushAccess.ExpiresAt)
+	if err != nil {
+		c.pushAccess.pushAccess = nil
+		return pushAccess, nil
+	}
+
+	c.pushAccess.pushAccess = pushAccess
+	c.pushAccess.expiresAt = expiresAt
+
+	return pushAccess, nil
+}
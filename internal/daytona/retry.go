@@ -0,0 +1,138 @@
+package daytona
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// RetryPolicy configures how RetryTransport retries failed requests.
+type RetryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	StatusCodes []int
+}
+
+// DefaultRetryPolicy retries rate-limited requests and transient server
+// errors a few times.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  time.Second,
+	MaxBackoff:  30 * time.Second,
+	StatusCodes: []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// RetryTransport retries API requests that failed with one of the policy's
+// status codes, backing off exponentially between attempts or for as long as
+// the Retry-After header asks. Requests that aren't idempotent, like creating
+// a snapshot, may have been processed by a failing server, so they are only
+// retried on responses that guarantee they weren't. Connection errors are
+// retried for idempotent requests only.
+type RetryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+func NewRetryTransport(next http.RoundTripper, policy RetryPolicy) *RetryTransport {
+	return &RetryTransport{
+		next:   next,
+		policy: policy,
+	}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	idempotent := idempotentMethod(req.Method)
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.policy.MaxAttempts || !replayable || ctx.Err() != nil {
+			return resp, err
+		}
+
+		var retry bool
+		if err != nil {
+			retry = idempotent
+		} else if slices.Contains(t.policy.StatusCodes, resp.StatusCode) {
+			// 429 and 503 are sent before the request is processed
+			retry = idempotent || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+		}
+		if !retry {
+			return resp, err
+		}
+
+		backoff := t.backoff(attempt, resp)
+
+		fields := map[string]any{
+			"method":  req.Method,
+			"path":    req.URL.Path,
+			"attempt": attempt,
+			"backoff": backoff.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+
+			// drain the body so the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		tflog.Debug(ctx, "Retrying API request", fields)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// backoff returns how long to wait before the next attempt: the Retry-After
+// of the response if it sends one, otherwise an exponentially growing delay
+// with jitter. Either is capped at the policy's maximum.
+// idempotentMethod reports whether sending a request with the method twice
+// has the same effect as sending it once.
+func idempotentMethod(method string) bool {
+	return slices.Contains([]string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete}, method)
+}
+
+func (t *RetryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, t.policy.MaxBackoff)
+		}
+	}
+
+	backoff := t.policy.MinBackoff << (attempt - 1)
+	if backoff < t.policy.MinBackoff || backoff > t.policy.MaxBackoff {
+		backoff = t.policy.MaxBackoff
+	}
+
+	// spread retries of parallel requests so they don't hit the API at once
+	return backoff/2 + rand.N(backoff/2+1)
+}
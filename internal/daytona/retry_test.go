@@ -0,0 +1,200 @@
+package daytona
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first requests with the given status codes and
+// answers the following ones with 200 and the body they were sent.
+type flakyServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+func newFlakyServer(t *testing.T, statuses ...int) *flakyServer {
+	t.Helper()
+
+	s := &flakyServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.bodies = append(s.bodies, string(body))
+		if len(s.bodies) <= len(s.statuses) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(s.statuses[len(s.bodies)-1])
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *flakyServer) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  time.Millisecond,
+	MaxBackoff:  10 * time.Millisecond,
+	StatusCodes: DefaultRetryPolicy.StatusCodes,
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := map[string]struct {
+		method           string
+		statuses         []int
+		expectedStatus   int
+		expectedAttempts int
+	}{
+		"success": {
+			method:           http.MethodGet,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 1,
+		},
+		"transient errors": {
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 3,
+		},
+		"attempts exhausted": {
+			method:           http.MethodDelete,
+			statuses:         []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			expectedStatus:   http.StatusInternalServerError,
+			expectedAttempts: 3,
+		},
+		"status not retried": {
+			method:           http.MethodGet,
+			statuses:         []int{http.StatusNotFound},
+			expectedStatus:   http.StatusNotFound,
+			expectedAttempts: 1,
+		},
+		"rate limited creation": {
+			method:           http.MethodPost,
+			statuses:         []int{http.StatusTooManyRequests},
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 2,
+		},
+		// the server may have created the snapshot before failing
+		"failed creation": {
+			method:           http.MethodPost,
+			statuses:         []int{http.StatusBadGateway},
+			expectedStatus:   http.StatusBadGateway,
+			expectedAttempts: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newFlakyServer(t, test.statuses...)
+			transport := NewRetryTransport(http.DefaultTransport, testRetryPolicy)
+
+			req, _ := http.NewRequest(test.method, server.URL, strings.NewReader("body"))
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := readBody(t, resp)
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, resp.StatusCode)
+			}
+			if server.attempts() != test.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", test.expectedAttempts, server.attempts())
+			}
+			if resp.StatusCode == http.StatusOK && body != "body" {
+				t.Errorf("expected the body to be sent again, got %q", body)
+			}
+		})
+	}
+}
+
+func TestRetryTransportUnreplayableBody(t *testing.T) {
+	server := newFlakyServer(t, http.StatusServiceUnavailable)
+	transport := NewRetryTransport(http.DefaultTransport, testRetryPolicy)
+
+	// a body without GetBody can't be sent again
+	req, _ := http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("body")))
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable || server.attempts() != 1 {
+		t.Errorf("expected a single attempt, got %d ending with %d", server.attempts(), resp.StatusCode)
+	}
+}
+
+// countingTransport counts the requests it sends.
+type countingTransport struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.requests++
+	t.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRetryTransportConnectionErrors(t *testing.T) {
+	unreachable := unreachableURL(t, "/")
+
+	// only idempotent requests are known not to have been processed
+	for method, expected := range map[string]int{http.MethodGet: 3, http.MethodPost: 1} {
+		counting := &countingTransport{}
+		transport := NewRetryTransport(counting, testRetryPolicy)
+
+		req, _ := http.NewRequest(method, unreachable.String(), nil)
+		if _, err := transport.RoundTrip(req); err == nil {
+			t.Errorf("expected %s to fail", method)
+		}
+		if counting.requests != expected {
+			t.Errorf("expected %d attempts of %s, got %d", expected, method, counting.requests)
+		}
+	}
+}
+
+func TestRetryTransportBackoff(t *testing.T) {
+	transport := NewRetryTransport(http.DefaultTransport, RetryPolicy{
+		MaxAttempts: 10,
+		MinBackoff:  time.Second,
+		MaxBackoff:  30 * time.Second,
+	})
+
+	retryAfter := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": {value}}}
+	}
+
+	if backoff := transport.backoff(1, retryAfter("5")); backoff != 5*time.Second {
+		t.Errorf("expected the Retry-After of 5s, got %s", backoff)
+	}
+	if backoff := transport.backoff(1, retryAfter("3600")); backoff != 30*time.Second {
+		t.Errorf("expected Retry-After to be capped at 30s, got %s", backoff)
+	}
+
+	for attempt, expected := range map[int]time.Duration{1: time.Second, 3: 4 * time.Second, 8: 30 * time.Second, 80: 30 * time.Second} {
+		// an invalid Retry-After falls back to the exponential backoff
+		backoff := transport.backoff(attempt, retryAfter("soon"))
+		if backoff < expected/2 || backoff > expected {
+			t.Errorf("expected the backoff of attempt %d within [%s, %s], got %s", attempt, expected/2, expected, backoff)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package daytona
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// queueWaitLogThreshold is how long a request has to wait for a free slot
+// before the wait gets logged.
+const queueWaitLogThreshold = 100 * time.Millisecond
+
+// ConcurrencyLimitTransport bounds the number of API requests in flight at
+// once. It's shared by every resource and data source of a provider instance,
+// so large applies don't flood the API with parallel polling.
+type ConcurrencyLimitTransport struct {
+	next  http.RoundTripper
+	slots chan struct{}
+}
+
+func NewConcurrencyLimitTransport(next http.RoundTripper, limit int) *ConcurrencyLimitTransport {
+	return &ConcurrencyLimitTransport{
+		next:  next,
+		slots: make(chan struct{}, limit),
+	}
+}
+
+func (t *ConcurrencyLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := time.Now()
+
+	select {
+	case t.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-t.slots }()
+
+	if wait := time.Since(start); wait >= queueWaitLogThreshold {
+		tflog.Debug(ctx, "Waited for a free API request slot", map[string]any{
+			"method":   req.Method,
+			"path":     req.URL.Path,
+			"wait":     wait.String(),
+			"capacity": cap(t.slots),
+		})
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// TimeoutTransport bounds each attempt of an API request, so a stalled
+// connection fails the attempt instead of hanging the provider. The timeout
+// covers reading the response body as well.
+type TimeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+func NewTimeoutTransport(next http.RoundTripper, timeout time.Duration) *TimeoutTransport {
+	return &TimeoutTransport{
+		next:    next,
+		timeout: timeout,
+	}
+}
+
+func (t *TimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnCloseBody releases the attempt's timeout once its body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// RateLimitTransport spaces out API requests so that no more than a burst of
+// them is sent at once and the rate averages out to the configured one. Like
+// the concurrency limit, it's shared by a whole provider instance.
+type RateLimitTransport struct {
+	next     http.RoundTripper
+	interval time.Duration
+	burst    int
+
+	mu sync.Mutex
+	// next slot the request rate would allow if no requests were bursting
+	nextSlot time.Time
+}
+
+func NewRateLimitTransport(next http.RoundTripper, requestsPerSecond float64, burst int) *RateLimitTransport {
+	return &RateLimitTransport{
+		next:     next,
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+		burst:    burst,
+	}
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if wait := t.reserve(); wait > 0 {
+		if wait >= queueWaitLogThreshold {
+			tflog.Debug(ctx, "Waiting for the API request rate limit", map[string]any{
+				"method": req.Method,
+				"path":   req.URL.Path,
+				"wait":   wait.String(),
+			})
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// reserve claims the next free slot and returns how long to wait for it.
+func (t *RateLimitTransport) reserve() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.nextSlot.Before(now) {
+		t.nextSlot = now
+	}
+
+	wait := t.nextSlot.Sub(now) - time.Duration(t.burst-1)*t.interval
+	t.nextSlot = t.nextSlot.Add(t.interval)
+	return wait
+}
@@ -0,0 +1,173 @@
+package daytona
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimitTransport(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	transport := NewConcurrencyLimitTransport(http.DefaultTransport, 2)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestConcurrencyLimitTransportCancelledWait(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	transport := NewConcurrencyLimitTransport(http.DefaultTransport, 1)
+
+	started := make(chan struct{})
+	go func() {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		close(started)
+		if resp, err := transport.RoundTrip(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+	time.Sleep(10 * time.Millisecond)
+
+	// the only slot is taken, so the request waits until it's cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait for a slot to be cancelled, got %v", err)
+	}
+}
+
+func TestTimeoutTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			select {
+			case <-time.After(time.Second):
+			case <-req.Context().Done():
+			}
+		}
+		w.Write([]byte("done"))
+	}))
+	t.Cleanup(server.Close)
+
+	transport := NewTimeoutTransport(http.DefaultTransport, 50*time.Millisecond)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/slow", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the stalled request to time out, got %v", err)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/fast", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := readBody(t, resp); body != "done" {
+		t.Errorf("expected the body to be readable within the timeout, got %q", body)
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	// a burst of 2 goes out at once, the other 2 requests are spaced 20ms apart
+	transport := NewRateLimitTransport(http.DefaultTransport, 50, 2)
+
+	start := time.Now()
+	for range 4 {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected the requests to take at least 40ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimitTransportReserve(t *testing.T) {
+	transport := NewRateLimitTransport(http.DefaultTransport, 10, 3)
+
+	var waits []time.Duration
+	for range 5 {
+		waits = append(waits, max(0, transport.reserve()))
+	}
+
+	for i, wait := range waits {
+		// the first 3 requests are the burst
+		expected := time.Duration(max(0, i-2)) * 100 * time.Millisecond
+		if wait > expected || wait < expected-10*time.Millisecond {
+			t.Errorf("expected request %d to wait about %s, got %s", i, expected, wait)
+		}
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	redacted := redactHeaders(http.Header{
+		"Authorization":             {"Bearer secret"},
+		"X-Registry-Auth":           {"credentials"},
+		"Cookie":                    {"session"},
+		"X-Daytona-Organization-Id": {"org"},
+		"Accept":                    {"application/json", "text/plain"},
+	})
+
+	expected := map[string]string{
+		"Authorization":             "<redacted>",
+		"X-Registry-Auth":           "<redacted>",
+		"Cookie":                    "<redacted>",
+		"X-Daytona-Organization-Id": "org",
+		"Accept":                    "application/json, text/plain",
+	}
+	for name, value := range expected {
+		if redacted[name] != value {
+			t.Errorf("expected %s to be logged as %q, got %q", name, value, redacted[name])
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package ephemeralresources
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+const apiKeyPrivateStateKey = "api_key_name"
+
+var _ ephemeral.EphemeralResource = &ApiKeyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &ApiKeyEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithClose = &ApiKeyEphemeralResource{}
+
+func NewApiKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &ApiKeyEphemeralResource{}
+}
+
+type ApiKeyEphemeralResource struct {
+	client *daytona.Client
+}
+
+type ApiKeyEphemeralResourceModel struct {
+	Name        types.String `tfsdk:"name"`
+	Permissions types.List   `tfsdk:"permissions"`
+	TTL         types.String `tfsdk:"ttl"`
+	Value       types.String `tfsdk:"value"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+	ExpiresAt   types.String `tfsdk:"expires_at"`
+}
+
+func (r *ApiKeyEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_key"
+}
+
+func (r *ApiKeyEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Mints a short-lived Daytona API key for the duration of a Terraform run and revokes it afterwards",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the API key. A unique name is generated when not set",
+				Optional:            true,
+				Computed:            true,
+			},
+			"permissions": schema.ListAttribute{
+				MarkdownDescription: "Permissions granted to the API key, e.g. `write:snapshots`",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+			"ttl": schema.StringAttribute{
+				MarkdownDescription: "How long the key stays valid if it isn't revoked, as a Go duration string. Defaults to `1h`",
+				Optional:            true,
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "The API key",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The creation timestamp of the API key",
+				Computed:            true,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "The expiration timestamp of the API key",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *ApiKeyEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *ApiKeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ApiKeyEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ttl := time.Hour
+	if !data.TTL.IsNull() {
+		var err error
+		ttl, err = time.ParseDuration(data.TTL.ValueString())
+		if err != nil || ttl <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ttl"),
+				"Invalid TTL",
+				fmt.Sprintf("ttl must be a positive duration such as \"30m\", got: %q", data.TTL.ValueString()),
+			)
+			return
+		}
+	}
+
+	name := data.Name.ValueString()
+	if data.Name.IsNull() {
+		suffix := make([]byte, 4)
+		if _, err := rand.Read(suffix); err != nil {
+			resp.Diagnostics.AddError("Name Generation Error", fmt.Sprintf("Unable to generate API key name: %v", err))
+			return
+		}
+		name = fmt.Sprintf("terraform-ephemeral-%s-%s", time.Now().UTC().Format("20060102150405"), hex.EncodeToString(suffix))
+	}
+
+	var permissions []string
+	resp.Diagnostics.Append(data.Permissions.ElementsAs(ctx, &permissions, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createRequest := apiclient.NewCreateApiKey(name, permissions)
+	createRequest.SetExpiresAt(time.Now().Add(ttl))
+
+	apiKey, httpResp, err := r.client.ApiKeysAPI.CreateApiKey(ctx).CreateApiKey(*createRequest).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create API key, got error: %v", err))
+		return
+	}
+
+	privateName, err := json.Marshal(apiKey.Name)
+	if err != nil {
+		resp.Diagnostics.AddError("Private State Error", fmt.Sprintf("Unable to encode API key name: %v", err))
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, apiKeyPrivateStateKey, privateName)...)
+
+	data.Name = types.StringValue(apiKey.Name)
+	data.Value = types.StringValue(apiKey.Value)
+	data.CreatedAt = types.StringValue(apiKey.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
+	data.ExpiresAt = types.StringNull()
+	if apiKey.ExpiresAt.IsSet() && apiKey.ExpiresAt.Get() != nil {
+		data.ExpiresAt = types.StringValue(apiKey.ExpiresAt.Get().Format("2006-01-02T15:04:05Z07:00"))
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (r *ApiKeyEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	privateName, diags := req.Private.GetKey(ctx, apiKeyPrivateStateKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || privateName == nil {
+		return
+	}
+
+	var name string
+	if err := json.Unmarshal(privateName, &name); err != nil {
+		resp.Diagnostics.AddError("Private State Error", fmt.Sprintf("Unable to decode API key name: %v", err))
+		return
+	}
+
+	httpResp, err := r.client.ApiKeysAPI.DeleteApiKey(ctx, name).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil && httpResp != nil && httpResp.StatusCode == 404 {
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to revoke API key %q, got error: %v", name, err))
+		return
+	}
+
+	tflog.Info(ctx, "Revoked ephemeral API key", map[string]any{
+		"api_key_name": name,
+	})
+}
@@ -0,0 +1,115 @@
+package ephemeralresources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ ephemeral.EphemeralResource = &PreviewAccessEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &PreviewAccessEphemeralResource{}
+
+func NewPreviewAccessEphemeralResource() ephemeral.EphemeralResource {
+	return &PreviewAccessEphemeralResource{}
+}
+
+type PreviewAccessEphemeralResource struct {
+	client *daytona.Client
+}
+
+type PreviewAccessEphemeralResourceModel struct {
+	SandboxId types.String `tfsdk:"sandbox_id"`
+	Port      types.Int64  `tfsdk:"port"`
+	Url       types.String `tfsdk:"url"`
+	Token     types.String `tfsdk:"token"`
+}
+
+func (r *PreviewAccessEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_preview_access"
+}
+
+func (r *PreviewAccessEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches the preview URL of a sandbox port together with an access token for it, e.g. for smoke tests against a protected preview during apply. " +
+			"Send the token in the `x-daytona-preview-token` header. Its lifetime is decided by Daytona and it can't be revoked by the provider",
+
+		Attributes: map[string]schema.Attribute{
+			"sandbox_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the sandbox",
+				Required:            true,
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "The sandbox port to preview",
+				Required:            true,
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The preview URL of the port",
+				Computed:            true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "The access token for the preview URL",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (r *PreviewAccessEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *PreviewAccessEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data PreviewAccessEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	port := data.Port.ValueInt64()
+	if port < 1 || port > 65535 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("port"),
+			"Invalid Port",
+			fmt.Sprintf("port must be between 1 and 65535, got: %d", port),
+		)
+		return
+	}
+
+	preview, httpResp, err := r.client.SandboxAPI.GetPortPreviewUrl(ctx, data.SandboxId.ValueString(), float32(port)).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to get preview URL for port %d of sandbox %q, got error: %v", port, data.SandboxId.ValueString(), err),
+		)
+		return
+	}
+
+	data.Url = types.StringValue(preview.Url)
+	data.Token = types.StringValue(preview.Token)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,115 @@
+package ephemeralresources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ ephemeral.EphemeralResource = &RegistryPushAccessEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &RegistryPushAccessEphemeralResource{}
+
+func NewRegistryPushAccessEphemeralResource() ephemeral.EphemeralResource {
+	return &RegistryPushAccessEphemeralResource{}
+}
+
+type RegistryPushAccessEphemeralResource struct {
+	client *daytona.Client
+}
+
+type RegistryPushAccessEphemeralResourceModel struct {
+	Username    types.String `tfsdk:"username"`
+	Secret      types.String `tfsdk:"secret"`
+	RegistryUrl types.String `tfsdk:"registry_url"`
+	RegistryId  types.String `tfsdk:"registry_id"`
+	Project     types.String `tfsdk:"project"`
+	ExpiresAt   types.String `tfsdk:"expires_at"`
+}
+
+func (r *RegistryPushAccessEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_push_access"
+}
+
+func (r *RegistryPushAccessEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches transient credentials for pushing images into Daytona's registry, e.g. for external build steps that push images themselves. " +
+			"Images are pushed as `<registry_url>/<project>/<image>`. The credentials expire on their own and are shared with the pushes done by `daytona_snapshot` while they are valid",
+
+		Attributes: map[string]schema.Attribute{
+			"username": schema.StringAttribute{
+				MarkdownDescription: "The username to log into the registry with",
+				Computed:            true,
+			},
+			"secret": schema.StringAttribute{
+				MarkdownDescription: "The password to log into the registry with",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"registry_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the registry",
+				Computed:            true,
+			},
+			"registry_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the registry",
+				Computed:            true,
+			},
+			"project": schema.StringAttribute{
+				MarkdownDescription: "The project within the registry to push images into",
+				Computed:            true,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "When the credentials expire",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *RegistryPushAccessEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *RegistryPushAccessEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data RegistryPushAccessEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pushAccess, err := r.client.TransientPushAccess(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to get registry push access, got error: %v", err),
+		)
+		return
+	}
+
+	data.Username = types.StringValue(pushAccess.Username)
+	data.Secret = types.StringValue(pushAccess.Secret)
+	data.RegistryUrl = types.StringValue(pushAccess.RegistryUrl)
+	data.RegistryId = types.StringValue(pushAccess.RegistryId)
+	data.Project = types.StringValue(pushAccess.Project)
+	data.ExpiresAt = types.StringValue(pushAccess.ExpiresAt)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
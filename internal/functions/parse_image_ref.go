@@ -0,0 +1,87 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distribution/reference"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &ParseImageRefFunction{}
+
+var imageRefAttributeTypes = map[string]attr.Type{
+	"registry":   types.StringType,
+	"repository": types.StringType,
+	"tag":        types.StringType,
+	"digest":     types.StringType,
+}
+
+func NewParseImageRefFunction() function.Function {
+	return &ParseImageRefFunction{}
+}
+
+type ParseImageRefFunction struct{}
+
+func (f *ParseImageRefFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_image_ref"
+}
+
+func (f *ParseImageRefFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Splits a container image reference into its parts",
+		MarkdownDescription: "Splits a container image reference into `registry`, `repository`, `tag` and `digest`, normalized the way Docker does: " +
+			"`ubuntu` has the registry `docker.io` and the repository `library/ubuntu`. `tag` and `digest` are null when the reference doesn't have them. " +
+			"Fails for invalid references",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "image",
+				MarkdownDescription: "The image reference to parse, e.g. `ghcr.io/org/app:1.0`",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: imageRefAttributeTypes,
+		},
+	}
+}
+
+func (f *ParseImageRefFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var image string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &image))
+	if resp.Error != nil {
+		return
+	}
+
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid image reference %q: %s", image, err))
+		return
+	}
+
+	tag := types.StringNull()
+	if tagged, ok := named.(reference.Tagged); ok {
+		tag = types.StringValue(tagged.Tag())
+	}
+
+	digest := types.StringNull()
+	if digested, ok := named.(reference.Digested); ok {
+		digest = types.StringValue(digested.Digest().String())
+	}
+
+	result, diags := types.ObjectValue(imageRefAttributeTypes, map[string]attr.Value{
+		"registry":   types.StringValue(reference.Domain(named)),
+		"repository": types.StringValue(reference.Path(named)),
+		"tag":        tag,
+		"digest":     digest,
+	})
+	resp.Error = function.ConcatFuncErrors(resp.Error, function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
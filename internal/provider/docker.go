@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+// parseDockerConfig selects the Docker daemon from docker_host and
+// docker_tls, or from the Docker CLI context named by docker_context.
+func parseDockerConfig(data DaytonaProviderModel) (config daytona.DockerConfig, diags diag.Diagnostics) {
+	if !data.DockerContext.IsNull() {
+		if !data.DockerHost.IsNull() || data.DockerTLS != nil {
+			diags.AddAttributeError(
+				path.Root("docker_context"),
+				"Conflicting Docker Configuration",
+				"docker_context can't be combined with docker_host or docker_tls, the context provides both.",
+			)
+			return
+		}
+
+		config, err := dockerContextConfig(data.DockerContext.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("docker_context"), "Invalid Docker Context", err.Error())
+		}
+		return config, diags
+	}
+
+	config.Host = data.DockerHost.ValueString()
+	if data.DockerTLS != nil {
+		config.TLS, diags = newTLSConfig(data.DockerTLS, path.Root("docker_tls"))
+	}
+	return
+}
+
+// dockerContextConfig reads the Docker endpoint of a context from the Docker
+// CLI's context store. The "default" context is the environment's daemon.
+func dockerContextConfig(name string) (config daytona.DockerConfig, err error) {
+	if name == "default" {
+		return config, nil
+	}
+
+	configDir := os.Getenv("DOCKER_CONFIG")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return config, err
+		}
+		configDir = filepath.Join(home, ".docker")
+	}
+
+	// the store keeps each context in a directory named by its hash
+	hash := sha256.Sum256([]byte(name))
+	id := hex.EncodeToString(hash[:])
+
+	content, err := os.ReadFile(filepath.Join(configDir, "contexts", "meta", id, "meta.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, fmt.Errorf("docker context %q not found", name)
+	} else if err != nil {
+		return config, err
+	}
+
+	var meta struct {
+		Endpoints map[string]struct {
+			Host          string `json:"Host"`
+			SkipTLSVerify bool   `json:"SkipTLSVerify"`
+		} `json:"Endpoints"`
+	}
+	if err := json.Unmarshal(content, &meta); err != nil {
+		return config, fmt.Errorf("unable to decode docker context %q: %w", name, err)
+	}
+
+	endpoint := meta.Endpoints["docker"]
+	if endpoint.Host == "" {
+		return config, fmt.Errorf("docker context %q has no docker endpoint", name)
+	}
+	if strings.HasPrefix(endpoint.Host, "ssh://") {
+		return config, fmt.Errorf("docker context %q connects over ssh, which isn't supported, use a tcp:// or unix:// host instead", name)
+	}
+	config.Host = endpoint.Host
+
+	tlsDir := filepath.Join(configDir, "contexts", "tls", id, "docker")
+	config.TLS, err = dockerContextTLS(tlsDir, endpoint.SkipTLSVerify)
+	if err != nil {
+		return config, fmt.Errorf("unable to load TLS settings of docker context %q: %w", name, err)
+	}
+	return config, nil
+}
+
+// dockerContextTLS loads the ca.pem, cert.pem and key.pem of a context, if it
+// has any.
+func dockerContextTLS(dir string, skipVerify bool) (*tls.Config, error) {
+	read := func(name string) ([]byte, error) {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return content, err
+	}
+
+	caPEM, err := read("ca.pem")
+	if err != nil {
+		return nil, err
+	}
+	certificatePEM, err := read("cert.pem")
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := read("key.pem")
+	if err != nil {
+		return nil, err
+	}
+
+	if caPEM == nil && certificatePEM == nil && !skipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: skipVerify,
+	}
+	if caPEM != nil {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no PEM encoded certificates found in ca.pem")
+		}
+	}
+	if certificatePEM != nil {
+		certificate, err := tls.X509KeyPair(certificatePEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
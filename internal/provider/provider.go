@@ -1,29 +1,101 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/net/http/httpproxy"
 
 	"github.com/geldata/terraform-provider-daytona/internal/datasources"
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+	"github.com/geldata/terraform-provider-daytona/internal/ephemeralresources"
+	"github.com/geldata/terraform-provider-daytona/internal/functions"
 	"github.com/geldata/terraform-provider-daytona/internal/resources"
 )
 
+// tokenExpiryWarningWindow is how long a token that can't be refreshed has to
+// remain valid at least, or else a warning is shown. operation_timeout extends
+// it when set to longer.
+const tokenExpiryWarningWindow = time.Hour
+
+// defaultRequestTimeout bounds API request attempts unless request_timeout is
+// set. It's generous, so large uploads don't need to be configured for.
+const defaultRequestTimeout = 5 * time.Minute
+
+// defaultOperationTimeout bounds waits for long-running operations unless
+// operation_timeout is set, so a stuck snapshot doesn't block a run forever.
+const defaultOperationTimeout = time.Hour
+
 var _ provider.Provider = &DaytonaProvider{}
+var _ provider.ProviderWithEphemeralResources = &DaytonaProvider{}
+var _ provider.ProviderWithFunctions = &DaytonaProvider{}
 
 type DaytonaProvider struct {
 	version string
 }
 
 type DaytonaProviderModel struct {
-	Token          types.String `tfsdk:"token"`
-	OrganizationID types.String `tfsdk:"organization_id"`
+	Token                    types.String  `tfsdk:"token"`
+	TokenFile                types.String  `tfsdk:"token_file"`
+	CredentialCommand        types.List    `tfsdk:"credential_command"`
+	OrganizationID           types.String  `tfsdk:"organization_id"`
+	OrganizationName         types.String  `tfsdk:"organization_name"`
+	MockMode                 types.Bool    `tfsdk:"mock_mode"`
+	MaxConcurrentAPIRequests types.Int64   `tfsdk:"max_concurrent_api_requests"`
+	MaxAPIRequestsPerSecond  types.Float64 `tfsdk:"max_api_requests_per_second"`
+	APIRequestBurst          types.Int64   `tfsdk:"api_request_burst"`
+	Endpoint                 types.String  `tfsdk:"endpoint"`
+	Endpoints                types.List    `tfsdk:"endpoints"`
+	ProxyURL                 types.String  `tfsdk:"proxy_url"`
+	NoProxy                  types.String  `tfsdk:"no_proxy"`
+	RequestTimeout           types.String  `tfsdk:"request_timeout"`
+	OperationTimeout         types.String  `tfsdk:"operation_timeout"`
+	DefaultHeaders           types.Map     `tfsdk:"default_headers"`
+	LogAPIRequests           types.Bool    `tfsdk:"log_api_requests"`
+	Retry                    *RetryModel   `tfsdk:"retry"`
+	TLS                      *TLSModel     `tfsdk:"tls"`
+	DockerHost               types.String  `tfsdk:"docker_host"`
+	DockerContext            types.String  `tfsdk:"docker_context"`
+	DockerTLS                *TLSModel     `tfsdk:"docker_tls"`
+	PushParallelism          types.Int64   `tfsdk:"push_parallelism"`
+}
+
+type RetryModel struct {
+	MaxAttempts          types.Int64  `tfsdk:"max_attempts"`
+	MinBackoff           types.String `tfsdk:"min_backoff"`
+	MaxBackoff           types.String `tfsdk:"max_backoff"`
+	RetryableStatusCodes types.List   `tfsdk:"retryable_status_codes"`
+}
+
+type TLSModel struct {
+	CACertificate      types.String `tfsdk:"ca_certificate"`
+	ClientCertificate  types.String `tfsdk:"client_certificate"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
 }
 
 func (p *DaytonaProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -36,13 +108,164 @@ func (p *DaytonaProvider) Schema(ctx context.Context, req provider.SchemaRequest
 		Description: "The Daytona provider is used to interact with Daytona resources through Terraform.",
 		Attributes: map[string]schema.Attribute{
 			"token": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "JWT token for authenticating with the Daytona API. Can also be set via DAYTONA_TOKEN environment variable. " +
+					"Provider configuration is never stored in state, and the token can be taken from an ephemeral variable or ephemeral resource to keep it out of plan files as well. Conflicts with token_file and credential_command.",
+			},
+			"token_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path of a file containing the token for authenticating with the Daytona API, e.g. a mounted Kubernetes secret. The file is read every time the provider is configured, and again when the token is about to expire or is rejected by the API. DAYTONA_TOKEN takes precedence over it. Conflicts with token and credential_command.",
+			},
+			"credential_command": schema.ListAttribute{
+				ElementType: types.StringType,
 				Optional:    true,
-				Sensitive:   true,
-				Description: "JWT token for authenticating with the Daytona API. Can also be set via DAYTONA_TOKEN environment variable.",
+				Description: "Credential helper printing the token for authenticating with the Daytona API, e.g. fetching it from Vault or 1Password, given as the program followed by its arguments. " +
+					"It prints either the bare token or a JSON object like {\"token\": \"...\", \"expires_at\": \"2025-01-01T00:00:00Z\"}. " +
+					"The command runs every time the provider is configured, and again when the token is about to expire or is rejected by the API. " +
+					"DAYTONA_TOKEN takes precedence over it. Conflicts with token and token_file.",
 			},
 			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Organization ID to use for requests.",
+				Optional:    true,
+				Description: "Organization ID to use for requests. Can also be set via DAYTONA_ORGANIZATION_ID environment variable. When neither this nor organization_name is set, the organization available to the token is used, preferring the personal one. Conflicts with organization_name.",
+			},
+			"organization_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the organization to use for requests. The ID is resolved from the organizations available to the token. Conflicts with organization_id.",
+			},
+			"mock_mode": schema.BoolAttribute{
+				Optional: true,
+				Description: "Serve all API calls from deterministic fake data instead of contacting Daytona. " +
+					"Intended for running validate and plan in CI without credentials or network access. " +
+					"Images can't be pushed, as the registry handed out for pushing doesn't exist, so resources that push one can't be applied.",
+			},
+			"max_concurrent_api_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of API requests the provider sends concurrently across all resources and data sources. Unlimited when not set.",
+			},
+			"max_api_requests_per_second": schema.Float64Attribute{
+				Optional:    true,
+				Description: "Maximum average rate of API requests the provider sends across all resources and data sources, e.g. to stay below the rate limits of Daytona. Retries count towards it as well. Unlimited when not set.",
+			},
+			"api_request_burst": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of API requests that can be sent at once before max_api_requests_per_second spaces them out. Requires max_api_requests_per_second. Defaults to 1.",
+			},
+			"endpoint": schema.StringAttribute{
+				Optional:    true,
+				Description: "Daytona API URL, e.g. of a self-hosted or staging deployment. Can also be set via DAYTONA_API_URL environment variable. Defaults to https://app.daytona.io/api. Conflicts with endpoints.",
+			},
+			"endpoints": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Daytona API URLs, e.g. several ingress points of a self-hosted deployment. Requests fail over to the next URL when one can't be reached. Requests that aren't idempotent, like creating a snapshot, only fail over when they couldn't be sent, so they aren't processed twice. Conflicts with endpoint.",
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional: true,
+				Description: "URL of the proxy to send API requests through, e.g. http://proxy.example.com:3128. Defaults to the HTTPS_PROXY and HTTP_PROXY environment variables. " +
+					"The proxy, no_proxy and tls also apply to the registries the provider talks to itself: when copying images with the `copy` image source and when pushing with `push_mode = \"direct\"`. " +
+					"Pushing with `push_mode = \"daemon\"` and building or pulling local images is done by the Docker daemon, which uses its own proxy and TLS configuration.",
+			},
+			"no_proxy": schema.StringAttribute{
+				Optional:    true,
+				Description: "Comma-separated hosts, domains and CIDRs that are reached without the proxy. Defaults to the NO_PROXY environment variable.",
+			},
+			"request_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum duration of a single API request attempt, including reading the response, e.g. \"30s\". Timed out attempts are retried like connection errors. Defaults to 5m, 0 disables the timeout.",
+			},
+			"operation_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum time to wait for Daytona to finish a long-running operation, like building a snapshot or starting a sandbox, e.g. \"30m\". Defaults to 1h, 0 waits without limit.",
+			},
+			"docker_host": schema.StringAttribute{
+				Optional:    true,
+				Description: "Docker daemon that builds and pushes images, e.g. \"tcp://docker.internal:2376\" or \"unix:///run/user/1000/docker.sock\". Podman and other engines serving the Docker API work as well. Defaults to the DOCKER_HOST environment variable, then to CONTAINER_HOST unless it's an ssh:// host, or else the local Docker daemon, falling back to Podman's socket if only Podman is running.",
+			},
+			"docker_context": schema.StringAttribute{
+				Optional:    true,
+				Description: "Docker CLI context to take the daemon and its TLS settings from, as created with `docker context create`. Conflicts with docker_host and docker_tls.",
+			},
+			"push_parallelism": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of layers uploaded at a time by images pushed with push_mode \"direct\". Defaults to 4.",
+			},
+			"default_headers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Additional HTTP headers sent with every API request, e.g. for tracing or routing. The Authorization and X-Daytona-Organization-ID headers are set by the provider and can't be overridden.",
+			},
+			"log_api_requests": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Log every API request attempt with its method, path, status, duration and headers at the INFO level, for debugging API issues. Credentials are redacted and bodies are never logged.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"retry": schema.SingleNestedBlock{
+				Description: "Retrying of API requests that failed with transient errors. Requests that create or change objects are only retried when the API reports that it didn't process them, i.e. on 429 and 503.",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Maximum number of attempts per request, 1 disables retries. Defaults to 4.",
+					},
+					"min_backoff": schema.StringAttribute{
+						Optional:    true,
+						Description: "Wait before the first retry, doubled for every further one. Defaults to 1s.",
+					},
+					"max_backoff": schema.StringAttribute{
+						Optional:    true,
+						Description: "Maximum wait between attempts, also capping waits requested through Retry-After. Defaults to 30s.",
+					},
+					"retryable_status_codes": schema.ListAttribute{
+						ElementType: types.Int64Type,
+						Optional:    true,
+						Description: "HTTP status codes that are retried. Defaults to 429, 500, 502, 503 and 504.",
+					},
+				},
+			},
+			"tls": schema.SingleNestedBlock{
+				Description: "TLS settings for API requests, e.g. for self-hosted deployments using an internal CA. Certificates and keys are given either as PEM or as the path of a PEM file.",
+				Attributes: map[string]schema.Attribute{
+					"ca_certificate": schema.StringAttribute{
+						Optional:    true,
+						Description: "CA certificates to trust in addition to the system ones.",
+					},
+					"client_certificate": schema.StringAttribute{
+						Optional:    true,
+						Description: "Client certificate to authenticate with. Requires client_key.",
+					},
+					"client_key": schema.StringAttribute{
+						Optional:    true,
+						Sensitive:   true,
+						Description: "Private key of the client certificate. Requires client_certificate.",
+					},
+					"insecure_skip_verify": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Don't verify the certificate of the API server. Only meant for testing.",
+					},
+				},
+			},
+			"docker_tls": schema.SingleNestedBlock{
+				Description: "TLS settings for a Docker daemon listening on tcp://, e.g. one protected with `--tlsverify`. Certificates and keys are given either as PEM or as the path of a PEM file.",
+				Attributes: map[string]schema.Attribute{
+					"ca_certificate": schema.StringAttribute{
+						Optional:    true,
+						Description: "CA certificates to trust in addition to the system ones.",
+					},
+					"client_certificate": schema.StringAttribute{
+						Optional:    true,
+						Description: "Client certificate to authenticate with. Requires client_key.",
+					},
+					"client_key": schema.StringAttribute{
+						Optional:    true,
+						Sensitive:   true,
+						Description: "Private key of the client certificate. Requires client_certificate.",
+					},
+					"insecure_skip_verify": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Don't verify the certificate of the Docker daemon. Only meant for testing.",
+					},
+				},
 			},
 		},
 	}
@@ -57,48 +280,729 @@ func (p *DaytonaProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	endpoint := "https://app.daytona.io/api"
+	if !data.Endpoint.IsNull() && !data.Endpoints.IsNull() {
+		resp.Diagnostics.AddError(
+			"Conflicting Endpoint Configuration",
+			"Only one of endpoint and endpoints can be set in the provider configuration.",
+		)
+		return
+	}
 
-	token := os.Getenv("DAYTONA_TOKEN")
-	if token == "" && !data.Token.IsNull() {
-		token = data.Token.ValueString()
+	rawEndpoints := []string{"https://app.daytona.io/api"}
+	endpointPath := func(int) path.Path { return path.Root("endpoint") }
+	if apiURL := os.Getenv("DAYTONA_API_URL"); apiURL != "" {
+		rawEndpoints = []string{apiURL}
+	}
+	if !data.Endpoint.IsNull() {
+		rawEndpoints = []string{data.Endpoint.ValueString()}
+	}
+	if !data.Endpoints.IsNull() {
+		rawEndpoints = nil
+		endpointPath = path.Root("endpoints").AtListIndex
+		resp.Diagnostics.Append(data.Endpoints.ElementsAs(ctx, &rawEndpoints, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
-	if token == "" {
+	endpoints, diags := parseEndpoints(rawEndpoints, endpointPath)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mockMode := data.MockMode.ValueBool()
+
+	tokenSources := 0
+	for _, value := range []attr.Value{data.Token, data.TokenFile, data.CredentialCommand} {
+		if !value.IsNull() {
+			tokenSources++
+		}
+	}
+	if tokenSources > 1 {
+		resp.Diagnostics.AddError(
+			"Conflicting Token Configuration",
+			"Only one of token, token_file and credential_command can be set in the provider configuration.",
+		)
+		return
+	}
+
+	var refreshToken func(ctx context.Context) (daytona.Credential, error)
+	var refreshTokenPath path.Path
+	if !data.TokenFile.IsNull() {
+		tokenFile := data.TokenFile.ValueString()
+		refreshToken = func(ctx context.Context) (daytona.Credential, error) {
+			token, err := readTokenFile(tokenFile)
+			return daytona.Credential{Token: token}, err
+		}
+		refreshTokenPath = path.Root("token_file")
+	}
+	if !data.CredentialCommand.IsNull() {
+		var command []string
+		resp.Diagnostics.Append(data.CredentialCommand.ElementsAs(ctx, &command, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if len(command) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credential_command"),
+				"Invalid Credential Command",
+				"credential_command must contain at least the program to run.",
+			)
+			return
+		}
+
+		refreshToken = func(ctx context.Context) (daytona.Credential, error) {
+			return runCredentialCommand(ctx, command)
+		}
+		refreshTokenPath = path.Root("credential_command")
+	}
+
+	credential := daytona.Credential{Token: os.Getenv("DAYTONA_TOKEN")}
+	if credential.Token != "" {
+		refreshToken = nil
+	}
+	if credential.Token == "" && !data.Token.IsNull() {
+		credential.Token = data.Token.ValueString()
+	}
+	if credential.Token == "" && refreshToken != nil {
+		var err error
+		credential, err = refreshToken(ctx)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(refreshTokenPath, "Unable to Get API Token", err.Error())
+			return
+		}
+	}
+
+	if !data.OrganizationID.IsNull() && !data.OrganizationName.IsNull() {
+		resp.Diagnostics.AddError(
+			"Conflicting Organization Configuration",
+			"Only one of organization_id and organization_name can be set in the provider configuration.",
+		)
+		return
+	}
+
+	if organizationID := os.Getenv("DAYTONA_ORGANIZATION_ID"); organizationID != "" && data.OrganizationID.IsNull() && data.OrganizationName.IsNull() {
+		data.OrganizationID = types.StringValue(organizationID)
+	}
+
+	if mockMode {
+		tflog.Warn(ctx, "Mock mode is enabled, no requests will be sent to the Daytona API")
+
+		if credential.Token == "" {
+			credential.Token = "mock"
+		}
+		if data.OrganizationID.IsNull() && data.OrganizationName.IsNull() {
+			data.OrganizationID = types.StringValue(daytona.MockOrganizationID)
+		}
+	}
+
+	if credential.Token == "" {
 		resp.Diagnostics.AddError(
 			"Missing API Token",
 			"The provider requires an API token to authenticate with Daytona. "+
-				"Set it in the provider configuration or use the DAYTONA_TOKEN environment variable.",
+				"Set token, token_file or credential_command in the provider configuration or use the DAYTONA_TOKEN environment variable.",
 		)
 		return
 	}
 
-	organizationID := data.OrganizationID.ValueString()
-
 	cfg := apiclient.NewConfiguration()
 	cfg.Servers = []apiclient.ServerConfiguration{{
-		URL: endpoint,
+		URL: endpoints[0].String(),
 	}}
-	cfg.DefaultHeader = map[string]string{
-		"Authorization":             "Bearer " + token,
-		"X-Daytona-Organization-ID": organizationID,
+	cfg.UserAgent = fmt.Sprintf("terraform-provider-daytona/%s Terraform/%s (%s; %s)", p.version, req.TerraformVersion, runtime.GOOS, runtime.GOARCH)
+	cfg.DefaultHeader = map[string]string{}
+	if !data.DefaultHeaders.IsNull() {
+		var headers map[string]string
+		resp.Diagnostics.Append(data.DefaultHeaders.ElementsAs(ctx, &headers, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for name, value := range headers {
+			switch http.CanonicalHeaderKey(name) {
+			case "Authorization", "X-Daytona-Organization-Id":
+				resp.Diagnostics.AddAttributeError(
+					path.Root("default_headers").AtMapKey(name),
+					"Invalid Default Header",
+					fmt.Sprintf("The %s header is set by the provider and can't be overridden.", name),
+				)
+				continue
+			}
+			cfg.DefaultHeader[name] = value
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	httpTransport, diags := newHTTPTransport(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	requestTimeout, operationTimeout, diags := parseTimeouts(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dockerConfig, diags := parseDockerConfig(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var pushParallelism int
+	if !data.PushParallelism.IsNull() {
+		parallelism := data.PushParallelism.ValueInt64()
+		if parallelism < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("push_parallelism"),
+				"Invalid Push Parallelism",
+				fmt.Sprintf("push_parallelism must be at least 1, got: %d", parallelism),
+			)
+			return
+		}
+		pushParallelism = int(parallelism)
+	}
+
+	tokenSource := daytona.NewTokenSource(credential, refreshToken)
+	if expiresAt := tokenSource.ExpiresAt(); !expiresAt.IsZero() && !tokenSource.Refreshable() {
+		if window := max(operationTimeout, tokenExpiryWarningWindow); time.Until(expiresAt) < window {
+			resp.Diagnostics.AddWarning(
+				"API Token Expires Soon",
+				fmt.Sprintf("The API token expires at %s, so long-running operations like pushing snapshots may fail. "+
+					"Use token_file or credential_command to have the provider refresh the token when it expires.", expiresAt.Format(time.RFC3339)),
+			)
+		}
+	}
+
+	var transport http.RoundTripper = httpTransport
+
+	// innermost, in place of the network, so the wrappers still apply
+	if mockMode {
+		organizationID := data.OrganizationID.ValueString()
+		if organizationID == "" {
+			organizationID = daytona.MockOrganizationID
+		}
+
+		transport = daytona.NewMockTransport(endpoints[0].Path, organizationID, data.OrganizationName.ValueString())
+	}
+
+	if data.LogAPIRequests.ValueBool() {
+		transport = daytona.NewLoggingTransport(transport)
+	}
+
+	// per endpoint, so idempotent requests to a stalled endpoint fail over to
+	// the next one
+	if requestTimeout > 0 {
+		transport = daytona.NewTimeoutTransport(transport, requestTimeout)
+	}
+
+	// the mock serves every endpoint alike, there is nothing to fail over to
+	if !mockMode && len(endpoints) > 1 {
+		transport = daytona.NewFailoverTransport(transport, endpoints)
+	}
+
+	if !data.MaxConcurrentAPIRequests.IsNull() {
+		limit := data.MaxConcurrentAPIRequests.ValueInt64()
+		if limit < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_concurrent_api_requests"),
+				"Invalid Concurrency Limit",
+				fmt.Sprintf("max_concurrent_api_requests must be at least 1, got: %d", limit),
+			)
+			return
+		}
+
+		transport = daytona.NewConcurrencyLimitTransport(transport, int(limit))
+	}
+
+	if !data.APIRequestBurst.IsNull() && data.MaxAPIRequestsPerSecond.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_request_burst"),
+			"Invalid Rate Limit",
+			"api_request_burst requires max_api_requests_per_second to be set.",
+		)
+		return
+	}
+
+	if !data.MaxAPIRequestsPerSecond.IsNull() {
+		rate := data.MaxAPIRequestsPerSecond.ValueFloat64()
+		if rate <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_api_requests_per_second"),
+				"Invalid Rate Limit",
+				fmt.Sprintf("max_api_requests_per_second must be greater than 0, got: %g", rate),
+			)
+			return
+		}
+
+		burst := int64(1)
+		if !data.APIRequestBurst.IsNull() {
+			burst = data.APIRequestBurst.ValueInt64()
+		}
+		if burst < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("api_request_burst"),
+				"Invalid Rate Limit",
+				fmt.Sprintf("api_request_burst must be at least 1, got: %d", burst),
+			)
+			return
+		}
+
+		// outside the concurrency limit, so waiting for the rate doesn't hold a slot
+		transport = daytona.NewRateLimitTransport(transport, rate, int(burst))
+	}
+
+	retryPolicy, diags := parseRetryPolicy(ctx, data.Retry)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// outside the concurrency limit, so backing off doesn't hold a slot
+	if retryPolicy.MaxAttempts > 1 {
+		transport = daytona.NewRetryTransport(transport, retryPolicy)
+	}
+
+	// outermost, so a rejected token is refreshed once for all attempts
+	transport = daytona.NewAuthTransport(transport, tokenSource)
+
+	cfg.HTTPClient = &http.Client{
+		Transport: transport,
 	}
 
 	apiClient := apiclient.NewAPIClient(cfg)
 
-	resp.DataSourceData = apiClient
-	resp.ResourceData = apiClient
+	organizationID := data.OrganizationID.ValueString()
+	if organizationID == "" {
+		organizationID, diags = resolveOrganizationID(ctx, apiClient, data.OrganizationName.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	cfg.DefaultHeader["X-Daytona-Organization-ID"] = organizationID
+
+	client := &daytona.Client{
+		APIClient:        apiClient,
+		OrganizationID:   organizationID,
+		OperationTimeout: operationTimeout,
+		Docker:           dockerConfig,
+		PushParallelism:  pushParallelism,
+		// the network transport only, the API's authentication, retries and
+		// limits don't apply to registries
+		HTTPClient: &http.Client{Transport: httpTransport},
+	}
+
+	resp.DataSourceData = client
+	resp.ResourceData = client
+	resp.EphemeralResourceData = client
+}
+
+// readTokenFile reads the API token from a file.
+func readTokenFile(name string) (string, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the API token from %q: %w", name, err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("the token file %q is empty", name)
+	}
+	return token, nil
+}
+
+// runCredentialCommand runs the credential helper and parses the credential
+// it printed, either a bare token or a JSON object with the token and its
+// expiry.
+func runCredentialCommand(ctx context.Context, command []string) (daytona.Credential, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return daytona.Credential{}, fmt.Errorf("credential command %q failed: %w: %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	output = bytes.TrimSpace(output)
+	if !bytes.HasPrefix(output, []byte("{")) {
+		if len(output) == 0 {
+			return daytona.Credential{}, fmt.Errorf("credential command %q printed no token", command[0])
+		}
+		return daytona.Credential{Token: string(output)}, nil
+	}
+
+	var credential struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(output, &credential); err != nil {
+		return daytona.Credential{}, fmt.Errorf("unable to parse the output of credential command %q: %w", command[0], err)
+	}
+	if credential.Token == "" {
+		return daytona.Credential{}, fmt.Errorf("credential command %q printed no token", command[0])
+	}
+
+	return daytona.Credential{Token: credential.Token, ExpiresAt: credential.ExpiresAt}, nil
+}
+
+func resolveOrganizationID(ctx context.Context, apiClient *apiclient.APIClient, organizationName string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	organizations, httpResp, err := apiClient.OrganizationsAPI.ListOrganizations(ctx).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list organizations, got error: %s", err))
+		return "", diags
+	}
+
+	if organizationName == "" {
+		return defaultOrganizationID(ctx, organizations)
+	}
+
+	var matches []apiclient.Organization
+	for _, organization := range organizations {
+		if organization.Name == organizationName {
+			matches = append(matches, organization)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		diags.AddError(
+			"Organization Not Found",
+			fmt.Sprintf("No organization named %q is available to the configured token", organizationName),
+		)
+		return "", diags
+	case 1:
+		tflog.Debug(ctx, "Resolved organization by name", map[string]any{
+			"organization_id":   matches[0].Id,
+			"organization_name": organizationName,
+		})
+		return matches[0].Id, diags
+	default:
+		diags.AddError(
+			"Ambiguous Organization Name",
+			fmt.Sprintf("Found %d organizations named %q, use organization_id instead", len(matches), organizationName),
+		)
+		return "", diags
+	}
+}
+
+// defaultOrganizationID picks the organization to use when none is
+// configured: the only one available to the token, or else the personal
+// organization of its user.
+func defaultOrganizationID(ctx context.Context, organizations []apiclient.Organization) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var organization *apiclient.Organization
+	if len(organizations) == 1 {
+		organization = &organizations[0]
+	} else if i := slices.IndexFunc(organizations, func(o apiclient.Organization) bool { return o.Personal }); i != -1 {
+		organization = &organizations[i]
+	}
+
+	if organization == nil {
+		diags.AddError(
+			"Missing Organization",
+			fmt.Sprintf("Found %d organizations available to the configured token and none of them is personal. "+
+				"Set either organization_id or organization_name in the provider configuration, or use the DAYTONA_ORGANIZATION_ID environment variable.", len(organizations)),
+		)
+		return "", diags
+	}
+
+	tflog.Debug(ctx, "Resolved default organization", map[string]any{
+		"organization_id":   organization.Id,
+		"organization_name": organization.Name,
+	})
+	return organization.Id, diags
+}
+
+// newHTTPTransport creates the transport that sends API requests, routed
+// through the configured proxy.
+func newHTTPTransport(data DaytonaProviderModel) (*http.Transport, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	proxyConfig := httpproxy.FromEnvironment()
+	if !data.ProxyURL.IsNull() {
+		proxyURL, err := url.Parse(data.ProxyURL.ValueString())
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			diags.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid Proxy URL",
+				fmt.Sprintf("proxy_url must be an absolute URL such as \"http://proxy.example.com:3128\", got: %q", data.ProxyURL.ValueString()),
+			)
+			return nil, diags
+		}
+
+		proxyConfig.HTTPProxy = proxyURL.String()
+		proxyConfig.HTTPSProxy = proxyURL.String()
+	}
+	if !data.NoProxy.IsNull() {
+		proxyConfig.NoProxy = data.NoProxy.ValueString()
+	}
+
+	proxy := proxyConfig.ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+
+	if data.TLS != nil {
+		transport.TLSClientConfig, diags = newTLSConfig(data.TLS, path.Root("tls"))
+	}
+
+	return transport, diags
+}
+
+// newTLSConfig creates a TLS configuration from the block at configPath.
+func newTLSConfig(config *TLSModel, configPath path.Path) (*tls.Config, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.InsecureSkipVerify.ValueBool(),
+	}
+
+	if !config.CACertificate.IsNull() {
+		caPEM, err := readPEM(config.CACertificate.ValueString())
+		if err != nil {
+			diags.AddAttributeError(configPath.AtName("ca_certificate"), "Invalid TLS Configuration", err.Error())
+			return nil, diags
+		}
+
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caPEM) {
+			diags.AddAttributeError(configPath.AtName("ca_certificate"), "Invalid TLS Configuration", "No PEM encoded certificates found in ca_certificate")
+			return nil, diags
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if config.ClientCertificate.IsNull() != config.ClientKey.IsNull() {
+		diags.AddAttributeError(configPath, "Invalid TLS Configuration", "client_certificate and client_key must be set together")
+		return nil, diags
+	}
+
+	if !config.ClientCertificate.IsNull() {
+		certificatePEM, err := readPEM(config.ClientCertificate.ValueString())
+		if err != nil {
+			diags.AddAttributeError(configPath.AtName("client_certificate"), "Invalid TLS Configuration", err.Error())
+			return nil, diags
+		}
+
+		keyPEM, err := readPEM(config.ClientKey.ValueString())
+		if err != nil {
+			diags.AddAttributeError(configPath.AtName("client_key"), "Invalid TLS Configuration", err.Error())
+			return nil, diags
+		}
+
+		certificate, err := tls.X509KeyPair(certificatePEM, keyPEM)
+		if err != nil {
+			diags.AddAttributeError(configPath.AtName("client_certificate"), "Invalid TLS Configuration", fmt.Sprintf("Unable to load client certificate: %v", err))
+			return nil, diags
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, diags
+}
+
+// readPEM returns PEM content as is and reads anything else as the path of
+// a PEM file.
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+
+	content, err := os.ReadFile(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read PEM file: %w", err)
+	}
+	return content, nil
+}
+
+// parseRetryPolicy applies the configured retry settings on top of the
+// default policy.
+func parseRetryPolicy(ctx context.Context, retry *RetryModel) (daytona.RetryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	policy := daytona.DefaultRetryPolicy
+	if retry == nil {
+		return policy, diags
+	}
+
+	if !retry.MaxAttempts.IsNull() {
+		policy.MaxAttempts = int(retry.MaxAttempts.ValueInt64())
+		if policy.MaxAttempts < 1 {
+			diags.AddAttributeError(
+				path.Root("retry").AtName("max_attempts"),
+				"Invalid Retry Configuration",
+				fmt.Sprintf("max_attempts must be at least 1, got: %d", policy.MaxAttempts),
+			)
+		}
+	}
+
+	for _, backoff := range []struct {
+		name  string
+		value types.String
+		field *time.Duration
+	}{
+		{"min_backoff", retry.MinBackoff, &policy.MinBackoff},
+		{"max_backoff", retry.MaxBackoff, &policy.MaxBackoff},
+	} {
+		if backoff.value.IsNull() {
+			continue
+		}
+
+		duration, err := time.ParseDuration(backoff.value.ValueString())
+		if err != nil || duration < 0 {
+			diags.AddAttributeError(
+				path.Root("retry").AtName(backoff.name),
+				"Invalid Retry Configuration",
+				fmt.Sprintf("%s must be a non-negative duration such as \"5s\", got: %q", backoff.name, backoff.value.ValueString()),
+			)
+			continue
+		}
+		*backoff.field = duration
+	}
+
+	if policy.MinBackoff > policy.MaxBackoff {
+		diags.AddAttributeError(
+			path.Root("retry").AtName("min_backoff"),
+			"Invalid Retry Configuration",
+			fmt.Sprintf("min_backoff (%s) must not be greater than max_backoff (%s)", policy.MinBackoff, policy.MaxBackoff),
+		)
+	}
+
+	if !retry.RetryableStatusCodes.IsNull() {
+		var statusCodes []int64
+		diags.Append(retry.RetryableStatusCodes.ElementsAs(ctx, &statusCodes, false)...)
+
+		policy.StatusCodes = make([]int, 0, len(statusCodes))
+		for _, statusCode := range statusCodes {
+			policy.StatusCodes = append(policy.StatusCodes, int(statusCode))
+		}
+	}
+
+	return policy, diags
+}
+
+// parseTimeouts parses the request and operation timeouts, falling back to
+// their defaults when not set.
+func parseTimeouts(data DaytonaProviderModel) (requestTimeout time.Duration, operationTimeout time.Duration, diags diag.Diagnostics) {
+	requestTimeout = defaultRequestTimeout
+	operationTimeout = defaultOperationTimeout
+
+	for _, timeout := range []struct {
+		name  string
+		value types.String
+		field *time.Duration
+	}{
+		{"request_timeout", data.RequestTimeout, &requestTimeout},
+		{"operation_timeout", data.OperationTimeout, &operationTimeout},
+	} {
+		if timeout.value.IsNull() {
+			continue
+		}
+
+		duration, err := time.ParseDuration(timeout.value.ValueString())
+		if err != nil || duration < 0 {
+			diags.AddAttributeError(
+				path.Root(timeout.name),
+				"Invalid Timeout",
+				fmt.Sprintf("%s must be a non-negative duration such as \"5m\", got: %q", timeout.name, timeout.value.ValueString()),
+			)
+			continue
+		}
+		*timeout.field = duration
+	}
+
+	return requestTimeout, operationTimeout, diags
+}
+
+// parseEndpoints parses the API URLs, reporting invalid ones at the path
+// returned by endpointPath for their index.
+func parseEndpoints(rawEndpoints []string, endpointPath func(int) path.Path) ([]*url.URL, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if len(rawEndpoints) == 0 {
+		diags.AddAttributeError(path.Root("endpoints"), "Missing Endpoints", "At least one API endpoint must be configured.")
+		return nil, diags
+	}
+
+	endpoints := make([]*url.URL, 0, len(rawEndpoints))
+	for i, rawEndpoint := range rawEndpoints {
+		endpoint, err := url.Parse(strings.TrimSuffix(rawEndpoint, "/"))
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			diags.AddAttributeError(
+				endpointPath(i),
+				"Invalid Endpoint",
+				fmt.Sprintf("Endpoint must be an absolute URL such as \"https://app.daytona.io/api\", got: %q", rawEndpoint),
+			)
+			continue
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, diags
 }
 
 func (p *DaytonaProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		resources.NewSnapshotResource,
+		resources.NewSnapshotBuildResource,
+		resources.NewSnapshotRetentionPolicyResource,
+		resources.NewPreviewLinkResource,
+		resources.NewRegistryImageResource,
+		resources.NewOrganizationRoleAssignmentResource,
+		resources.NewRoleResource,
+		resources.NewRegistryResource,
+		resources.NewSandboxExecResource,
+		resources.NewSandboxFileResource,
+		resources.NewUsageLimitResource,
 	}
 }
 
 func (p *DaytonaProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		datasources.NewSnapshotDataSource,
+		datasources.NewSnapshotsDataSource,
+		datasources.NewDefaultSnapshotDataSource,
+		datasources.NewSandboxesDataSource,
+		datasources.NewRateLimitDataSource,
+		datasources.NewRunnerDataSource,
+		datasources.NewRunnersDataSource,
+		datasources.NewOrganizationDataSource,
+		datasources.NewCurrentOrganizationDataSource,
+		datasources.NewRegistriesDataSource,
+	}
+}
+
+func (p *DaytonaProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		ephemeralresources.NewApiKeyEphemeralResource,
+		ephemeralresources.NewPreviewAccessEphemeralResource,
+		ephemeralresources.NewRegistryPushAccessEphemeralResource,
+	}
+}
+
+func (p *DaytonaProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		functions.NewParseImageRefFunction,
 	}
 }
 
@@ -0,0 +1,63 @@
+package resources
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/service"
+)
+
+func destroyBehaviorAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "What happens to the snapshot in Daytona when the Terraform resource is destroyed or replaced: `delete` removes it, `deactivate` keeps it listed but unusable for new sandboxes, `retain` leaves it as is. As snapshot names are unique, a snapshot kept by `deactivate` or `retain` can only be replaced by one with another name. Defaults to `delete`",
+		Optional:            true,
+		Computed:            true,
+		Default:             stringdefault.StaticString(service.DestroyBehaviorDelete),
+		Validators: []validator.String{
+			stringvalidator.OneOf(service.DestroyBehaviorDelete, service.DestroyBehaviorDeactivate, service.DestroyBehaviorRetain),
+		},
+	}
+}
+
+func keepRemotelyAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether to keep the snapshot in Daytona when the Terraform resource is destroyed. Deprecated, use `destroy_behavior = \"retain\"` instead",
+		DeprecationMessage:  "Use destroy_behavior = \"retain\" instead.",
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+	}
+}
+
+// validateDestroyBehavior rejects keep_remotely along with a destroy_behavior
+// other than retain.
+func validateDestroyBehavior(keepRemotely types.Bool, destroyBehavior types.String) (diags diag.Diagnostics) {
+	if keepRemotely.ValueBool() && !destroyBehavior.IsNull() && !destroyBehavior.IsUnknown() &&
+		destroyBehavior.ValueString() != service.DestroyBehaviorRetain {
+		diags.AddAttributeError(
+			path.Root("keep_remotely"),
+			"Conflicting Destroy Behavior",
+			"keep_remotely = true retains the snapshot, which contradicts destroy_behavior. Remove keep_remotely.",
+		)
+	}
+	return
+}
+
+// destroyBehavior returns the effective destroy behavior, where the deprecated
+// keep_remotely retains the snapshot.
+func destroyBehavior(keepRemotely types.Bool, destroyBehavior types.String) string {
+	if keepRemotely.ValueBool() {
+		return service.DestroyBehaviorRetain
+	}
+	if destroyBehavior.IsNull() {
+		// state written before destroy_behavior existed
+		return service.DestroyBehaviorDelete
+	}
+	return destroyBehavior.ValueString()
+}
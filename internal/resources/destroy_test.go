@@ -0,0 +1,54 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/service"
+)
+
+func TestValidateDestroyBehavior(t *testing.T) {
+	tests := map[string]struct {
+		keepRemotely    types.Bool
+		destroyBehavior types.String
+		expected        []string
+	}{
+		"defaults":                   {keepRemotely: types.BoolNull(), destroyBehavior: types.StringNull()},
+		"keep_remotely alone":        {keepRemotely: types.BoolValue(true), destroyBehavior: types.StringNull()},
+		"keep_remotely with retain":  {keepRemotely: types.BoolValue(true), destroyBehavior: types.StringValue(service.DestroyBehaviorRetain)},
+		"keep_remotely with unknown": {keepRemotely: types.BoolValue(true), destroyBehavior: types.StringUnknown()},
+		"keep_remotely with delete": {
+			keepRemotely:    types.BoolValue(true),
+			destroyBehavior: types.StringValue(service.DestroyBehaviorDelete),
+			expected:        []string{"Conflicting Destroy Behavior"},
+		},
+		"keep_remotely false with deactivate": {keepRemotely: types.BoolValue(false), destroyBehavior: types.StringValue(service.DestroyBehaviorDeactivate)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			requireErrors(t, validateDestroyBehavior(test.keepRemotely, test.destroyBehavior), test.expected...)
+		})
+	}
+}
+
+func TestDestroyBehavior(t *testing.T) {
+	tests := map[string]struct {
+		keepRemotely    types.Bool
+		destroyBehavior types.String
+		expected        string
+	}{
+		"state before destroy_behavior": {keepRemotely: types.BoolNull(), destroyBehavior: types.StringNull(), expected: service.DestroyBehaviorDelete},
+		"keep_remotely":                 {keepRemotely: types.BoolValue(true), destroyBehavior: types.StringValue(service.DestroyBehaviorDelete), expected: service.DestroyBehaviorRetain},
+		"destroy_behavior":              {keepRemotely: types.BoolValue(false), destroyBehavior: types.StringValue(service.DestroyBehaviorDeactivate), expected: service.DestroyBehaviorDeactivate},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if behavior := destroyBehavior(test.keepRemotely, test.destroyBehavior); behavior != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, behavior)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package resources
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/service"
+)
+
+func defaultImageSources() types.List {
+	return types.ListValueMust(types.StringType, []attr.Value{types.StringValue(service.ImageSourceLocal)})
+}
+
+func pushModeAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "How the image is pushed into Daytona's registry: `daemon` has the Docker daemon push it, `direct` uploads it from a `docker save` export, several layers at a time as set by the provider's `push_parallelism`. " +
+			"With the `archive` image source first, `direct` reads `image_archive` without a Docker daemon. `keep_local_tag` has no effect with `direct`, which doesn't tag the image locally. " +
+			"Images with the `copy` source are always copied between the registries. Changing it only affects later pushes. Defaults to `daemon`",
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString(service.PushModeDaemon),
+		Validators: []validator.String{
+			stringvalidator.OneOf(service.PushModeDaemon, service.PushModeDirect),
+		},
+	}
+}
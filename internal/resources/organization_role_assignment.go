@@ -0,0 +1,278 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+const (
+	organizationRoleOwner  = "owner"
+	organizationRoleMember = "member"
+)
+
+var _ resource.Resource = &OrganizationRoleAssignmentResource{}
+var _ resource.ResourceWithImportState = &OrganizationRoleAssignmentResource{}
+
+func NewOrganizationRoleAssignmentResource() resource.Resource {
+	return &OrganizationRoleAssignmentResource{}
+}
+
+type OrganizationRoleAssignmentResource struct {
+	client *daytona.Client
+}
+
+type OrganizationRoleAssignmentResourceModel struct {
+	Id              types.String `tfsdk:"id"`
+	UserId          types.String `tfsdk:"user_id"`
+	Role            types.String `tfsdk:"role"`
+	AssignedRoleIds types.Set    `tfsdk:"assigned_role_ids"`
+	Name            types.String `tfsdk:"name"`
+	Email           types.String `tfsdk:"email"`
+}
+
+func (r *OrganizationRoleAssignmentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_organization_role_assignment"
+}
+
+func (r *OrganizationRoleAssignmentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the role of a member of the provider's organization. Destroying the resource demotes the user to a `member` without assigned roles, it doesn't remove them from the organization",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the user",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the organization member",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role": schema.StringAttribute{
+				MarkdownDescription: "The member role, either `owner` or `member`",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(organizationRoleOwner, organizationRoleMember),
+				},
+			},
+			"assigned_role_ids": schema.SetAttribute{
+				MarkdownDescription: "IDs of the organization roles, e.g. developer, viewer or custom roles, assigned to a `member`. Defaults to no assigned roles",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, nil)),
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the user",
+				Computed:            true,
+			},
+			"email": schema.StringAttribute{
+				MarkdownDescription: "The email address of the user",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *OrganizationRoleAssignmentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OrganizationRoleAssignmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *OrganizationRoleAssignmentResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.assignRoles(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OrganizationRoleAssignmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *OrganizationRoleAssignmentResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	member, diags := r.findMember(ctx, data.UserId.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if member == nil {
+		tflog.Info(ctx, "User is no longer a member of the organization, removing role assignment from state", map[string]any{
+			"user_id": data.UserId.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(data.setMember(ctx, member)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OrganizationRoleAssignmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *OrganizationRoleAssignmentResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.assignRoles(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OrganizationRoleAssignmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *OrganizationRoleAssignmentResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	member, diags := r.findMember(ctx, data.UserId.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || member == nil {
+		return
+	}
+
+	data.Role = types.StringValue(organizationRoleMember)
+	data.AssignedRoleIds = types.SetValueMust(types.StringType, nil)
+	resp.Diagnostics.Append(r.assignRoles(ctx, data)...)
+}
+
+func (r *OrganizationRoleAssignmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_id"), req.ID)...)
+}
+
+// assignRoles sets the member role and the assigned roles of the user and
+// fills in the model from the API's answer.
+func (r *OrganizationRoleAssignmentResource) assignRoles(ctx context.Context, data *OrganizationRoleAssignmentResourceModel) (diags diag.Diagnostics) {
+	userID := data.UserId.ValueString()
+
+	var roleIDs []string
+	diags.Append(data.AssignedRoleIds.ElementsAs(ctx, &roleIDs, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	_, httpResp, err := r.client.OrganizationsAPI.UpdateRoleForOrganizationMember(ctx, r.client.OrganizationID, userID).
+		UpdateOrganizationMemberRole(*apiclient.NewUpdateOrganizationMemberRole(data.Role.ValueString())).
+		Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to update role of organization member %q, got error: %s", userID, err))
+		return
+	}
+
+	if roleIDs == nil {
+		roleIDs = []string{}
+	}
+
+	member, httpResp, err := r.client.OrganizationsAPI.UpdateAssignedOrganizationRoles(ctx, r.client.OrganizationID, userID).
+		UpdateAssignedOrganizationRoles(*apiclient.NewUpdateAssignedOrganizationRoles(roleIDs)).
+		Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to update assigned roles of organization member %q, got error: %s", userID, err))
+		return
+	}
+
+	diags.Append(data.setMember(ctx, member)...)
+	return
+}
+
+// findMember looks the user up among the organization's members, returning
+// nil if they aren't a member.
+func (r *OrganizationRoleAssignmentResource) findMember(ctx context.Context, userID string) (member *apiclient.OrganizationUser, diags diag.Diagnostics) {
+	members, httpResp, err := r.client.OrganizationsAPI.ListOrganizationMembers(ctx, r.client.OrganizationID).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list organization members, got error: %s", err))
+		return
+	}
+
+	for i := range members {
+		if members[i].UserId == userID {
+			member = &members[i]
+			return
+		}
+	}
+
+	return
+}
+
+// setMember fills in the attributes reported by the API.
+func (m *OrganizationRoleAssignmentResourceModel) setMember(ctx context.Context, member *apiclient.OrganizationUser) (diags diag.Diagnostics) {
+	roleIDs := make([]string, 0, len(member.AssignedRoles))
+	for _, role := range member.AssignedRoles {
+		roleIDs = append(roleIDs, role.Id)
+	}
+
+	assignedRoleIDs, diags := types.SetValueFrom(ctx, types.StringType, roleIDs)
+	if diags.HasError() {
+		return
+	}
+
+	m.Id = types.StringValue(member.UserId)
+	m.UserId = types.StringValue(member.UserId)
+	m.Role = types.StringValue(member.Role)
+	m.AssignedRoleIds = assignedRoleIDs
+	m.Name = types.StringValue(member.Name)
+	m.Email = types.StringValue(member.Email)
+	return
+}
@@ -0,0 +1,244 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ resource.Resource = &PreviewLinkResource{}
+var _ resource.ResourceWithValidateConfig = &PreviewLinkResource{}
+
+func NewPreviewLinkResource() resource.Resource {
+	return &PreviewLinkResource{}
+}
+
+type PreviewLinkResource struct {
+	client *daytona.Client
+}
+
+type PreviewLinkResourceModel struct {
+	Id        types.String `tfsdk:"id"`
+	SandboxId types.String `tfsdk:"sandbox_id"`
+	Port      types.Int64  `tfsdk:"port"`
+	Public    types.Bool   `tfsdk:"public"`
+	Url       types.String `tfsdk:"url"`
+	Token     types.String `tfsdk:"token"`
+}
+
+func (r *PreviewLinkResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_preview_link"
+}
+
+func (r *PreviewLinkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Publishes a sandbox port as a preview link. With `public` enabled the sandbox's previews are reachable without a token until the resource is destroyed. " +
+			"Daytona decides the lifetime of the access token and it can't be revoked by the provider. Use `daytona_preview_access` to keep the token out of the state",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The sandbox ID and port, separated by a colon",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sandbox_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the sandbox",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "The sandbox port to publish",
+				Required:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"public": schema.BoolAttribute{
+				MarkdownDescription: "Whether to make the sandbox's previews public, so they don't need the token. Daytona applies this to all ports of the sandbox, and destroying the resource makes them private again",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The preview URL of the port",
+				Computed:            true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "The access token for the preview URL, sent in the `x-daytona-preview-token` header",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (r *PreviewLinkResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var port types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("port"), &port)...)
+	if resp.Diagnostics.HasError() || port.IsNull() || port.IsUnknown() {
+		return
+	}
+
+	if port.ValueInt64() < 1 || port.ValueInt64() > 65535 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("port"),
+			"Invalid Port",
+			fmt.Sprintf("port must be between 1 and 65535, got: %d", port.ValueInt64()),
+		)
+	}
+}
+
+func (r *PreviewLinkResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *PreviewLinkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *PreviewLinkResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Public.ValueBool() {
+		resp.Diagnostics.Append(r.setPublic(ctx, data.SandboxId.ValueString(), true)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	_, diags := r.fetchPreview(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(fmt.Sprintf("%s:%d", data.SandboxId.ValueString(), data.Port.ValueInt64()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PreviewLinkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *PreviewLinkResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, diags := r.fetchPreview(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		tflog.Info(ctx, "Sandbox no longer exists, removing preview link from state", map[string]any{
+			"sandbox_id": data.SandboxId.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PreviewLinkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *PreviewLinkResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// only public can change in place
+	resp.Diagnostics.Append(r.setPublic(ctx, data.SandboxId.ValueString(), data.Public.ValueBool())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags := r.fetchPreview(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PreviewLinkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *PreviewLinkResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || !data.Public.ValueBool() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.setPublic(ctx, data.SandboxId.ValueString(), false)...)
+}
+
+// fetchPreview fills in the preview URL and token of the port. It reports
+// whether the sandbox still exists.
+func (r *PreviewLinkResource) fetchPreview(ctx context.Context, data *PreviewLinkResourceModel) (found bool, diags diag.Diagnostics) {
+	port := data.Port.ValueInt64()
+
+	preview, httpResp, err := r.client.SandboxAPI.GetPortPreviewUrl(ctx, data.SandboxId.ValueString(), float32(port)).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		if httpResp != nil && httpResp.StatusCode == 404 {
+			return false, diags
+		}
+
+		diags.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to get preview URL for port %d of sandbox %q, got error: %v", port, data.SandboxId.ValueString(), err),
+		)
+		return false, diags
+	}
+
+	data.Url = types.StringValue(preview.Url)
+	data.Token = types.StringValue(preview.Token)
+	return true, diags
+}
+
+func (r *PreviewLinkResource) setPublic(ctx context.Context, sandboxID string, public bool) (diags diag.Diagnostics) {
+	httpResp, err := r.client.SandboxAPI.UpdatePublicStatus(ctx, sandboxID, public).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil && (public || httpResp == nil || httpResp.StatusCode != 404) {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to update public status of sandbox %q, got error: %v", sandboxID, err))
+	}
+	return
+}
@@ -0,0 +1,244 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+// registryTypeOrganization is the type of registries owned by an
+// organization, as opposed to Daytona's internal registries.
+const registryTypeOrganization = "organization"
+
+var _ resource.Resource = &RegistryResource{}
+var _ resource.ResourceWithImportState = &RegistryResource{}
+
+func NewRegistryResource() resource.Resource {
+	return &RegistryResource{}
+}
+
+type RegistryResource struct {
+	client *daytona.Client
+}
+
+type RegistryResourceModel struct {
+	Id           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Url          types.String `tfsdk:"url"`
+	Username     types.String `tfsdk:"username"`
+	Password     types.String `tfsdk:"password"`
+	Project      types.String `tfsdk:"project"`
+	RegistryType types.String `tfsdk:"registry_type"`
+	CreatedAt    types.String `tfsdk:"created_at"`
+}
+
+func (r *RegistryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry"
+}
+
+func (r *RegistryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Registers an external container registry with the organization, so sandboxes and snapshots can use private images from it",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the registry",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the registry",
+				Required:            true,
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the registry, e.g. `ghcr.io`",
+				Required:            true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "The username to authenticate to the registry with",
+				Required:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password or access token to authenticate to the registry with. The API never returns it, so changes made outside Terraform aren't detected and imported registries have no password in state until it is set",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"project": schema.StringAttribute{
+				MarkdownDescription: "The project or namespace within the registry, if it has any",
+				Optional:            true,
+				Computed:            true,
+			},
+			"registry_type": schema.StringAttribute{
+				MarkdownDescription: "The type of the registry",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The creation timestamp of the registry",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *RegistryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *RegistryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *RegistryResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createRequest := apiclient.NewCreateDockerRegistry(
+		data.Name.ValueString(),
+		data.Url.ValueString(),
+		data.Username.ValueString(),
+		data.Password.ValueString(),
+		registryTypeOrganization,
+	)
+	if !data.Project.IsNull() && !data.Project.IsUnknown() {
+		createRequest.SetProject(data.Project.ValueString())
+	}
+
+	registry, httpResp, err := r.client.DockerRegistryAPI.CreateRegistry(ctx).CreateDockerRegistry(*createRequest).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create registry, got error: %s", err))
+		return
+	}
+
+	data.setRegistry(registry)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *RegistryResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registry, httpResp, err := r.client.DockerRegistryAPI.GetRegistry(ctx, data.Id.ValueString()).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		if httpResp != nil && httpResp.StatusCode == 404 {
+			tflog.Info(ctx, "Registry no longer exists, removing it from state", map[string]any{
+				"registry_id": data.Id.ValueString(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read registry, got error: %s", err))
+		return
+	}
+
+	data.setRegistry(registry)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *RegistryResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateRequest := apiclient.NewUpdateDockerRegistry(
+		data.Name.ValueString(),
+		data.Url.ValueString(),
+		data.Username.ValueString(),
+	)
+	updateRequest.SetPassword(data.Password.ValueString())
+	if !data.Project.IsNull() && !data.Project.IsUnknown() {
+		updateRequest.SetProject(data.Project.ValueString())
+	}
+
+	registry, httpResp, err := r.client.DockerRegistryAPI.UpdateRegistry(ctx, data.Id.ValueString()).UpdateDockerRegistry(*updateRequest).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update registry, got error: %s", err))
+		return
+	}
+
+	data.setRegistry(registry)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *RegistryResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	httpResp, err := r.client.DockerRegistryAPI.DeleteRegistry(ctx, data.Id.ValueString()).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil && (httpResp == nil || httpResp.StatusCode != 404) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete registry, got error: %s", err))
+	}
+}
+
+func (r *RegistryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// setRegistry fills in the attributes reported by the API. The password is
+// never returned and stays as configured.
+func (m *RegistryResourceModel) setRegistry(registry *apiclient.DockerRegistry) {
+	m.Id = types.StringValue(registry.Id)
+	m.Name = types.StringValue(registry.Name)
+	m.Url = types.StringValue(registry.Url)
+	m.Username = types.StringValue(registry.Username)
+	m.Project = types.StringValue(registry.Project)
+	m.RegistryType = types.StringValue(registry.RegistryType)
+	m.CreatedAt = types.StringValue(registry.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
+}
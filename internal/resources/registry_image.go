@@ -0,0 +1,227 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+	"github.com/geldata/terraform-provider-daytona/internal/service"
+	"github.com/geldata/terraform-provider-daytona/internal/validators"
+)
+
+var _ resource.Resource = &RegistryImageResource{}
+
+func NewRegistryImageResource() resource.Resource {
+	return &RegistryImageResource{}
+}
+
+type RegistryImageResource struct {
+	service *service.Service
+}
+
+type RegistryImageResourceModel struct {
+	Id              types.String `tfsdk:"id"`
+	ImageName       types.String `tfsdk:"image_name"`
+	ImageSources    types.List   `tfsdk:"image_sources"`
+	ImageArchive    types.String `tfsdk:"image_archive"`
+	KeepLocalTag    types.Bool   `tfsdk:"keep_local_tag"`
+	Platform        types.String `tfsdk:"platform"`
+	RemoteTag       types.String `tfsdk:"remote_tag"`
+	PushMode        types.String `tfsdk:"push_mode"`
+	RemoteImageName types.String `tfsdk:"remote_image_name"`
+	Digest          types.String `tfsdk:"digest"`
+}
+
+func (r *RegistryImageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_image"
+}
+
+func (r *RegistryImageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Pushes a local image into Daytona's container registry without registering a snapshot. Destroying the resource only removes it from the Terraform state, the pushed image stays in the registry",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The digest-pinned remote reference of the pushed image",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"image_name": schema.StringAttribute{
+				MarkdownDescription: "The local container image name to push",
+				Required:            true,
+				Validators: []validator.String{
+					validators.ImageReference(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							resp.RequiresReplace = !service.SameImageReference(req.PlanValue.ValueString(), req.StateValue.ValueString())
+						},
+						"Changing the image name to a different image pushes it again",
+						"Changing the image name to a different image pushes it again",
+					),
+				},
+			},
+			"image_sources": schema.ListAttribute{
+				MarkdownDescription: "Ordered list of places to source `image_name` from before pushing it: `local` uses the image already present in the Docker daemon, `archive` loads it from the `image_archive` tarball, `registry` pulls it from its remote registry. The first source that provides the image wins. `copy` instead copies it from its remote registry straight into Daytona's registry, without a Docker daemon, and can't be combined with other sources; credentials for the remote registry come from the Docker CLI's config. Defaults to `[\"local\"]`",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				Default:             listdefault.StaticValue(defaultImageSources()),
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(service.ImageSourceLocal, service.ImageSourceArchive, service.ImageSourceRegistry, service.ImageSourceCopy)),
+				},
+			},
+			"image_archive": schema.StringAttribute{
+				MarkdownDescription: "Path to a `docker save` tarball containing `image_name`. Used by the `archive` image source",
+				Optional:            true,
+			},
+			"keep_local_tag": schema.BoolAttribute{
+				MarkdownDescription: "Whether to keep the `remote_image_name` tag in the local Docker daemon after pushing instead of removing it",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Platform of the image to push, as `os/arch[/variant]`, e.g. `linux/amd64`. Selects the platform when pulling or copying multi-platform images, and fails the push if the local image was built for another one. " +
+					"Defaults to whatever the Docker daemon or registry provides",
+				Optional: true,
+				Validators: []validator.String{
+					validators.Platform(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"remote_tag": schema.StringAttribute{
+				MarkdownDescription: "Tag of the image in Daytona's registry. May use the placeholders `{timestamp}` for the time of the push, `{tag}` for the tag of `image_name` and `{digest}` for the first 12 hex digits of the local image's digest, which copied images only have when `image_name` is pinned by digest. " +
+					"A git SHA or other values can be passed in through variables, e.g. `\"${var.git_sha}\"`. Defaults to `{timestamp}`",
+				Optional: true,
+				Validators: []validator.String{
+					validators.TagTemplate(service.RemoteTagPlaceholders...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"push_mode": pushModeAttribute(),
+			"remote_image_name": schema.StringAttribute{
+				MarkdownDescription: "The remote image name in Daytona's registry. Pass it as `remote_image_name` of `daytona_snapshot` resources to register snapshots from the pushed image",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"digest": schema.StringAttribute{
+				MarkdownDescription: "The content digest of the pushed image",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *RegistryImageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.service = service.New(client)
+}
+
+func (r *RegistryImageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *RegistryImageResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec := service.ImageSpec{
+		ImageName:    data.ImageName.ValueString(),
+		ImageArchive: data.ImageArchive.ValueString(),
+		KeepLocalTag: data.KeepLocalTag.ValueBool(),
+		Platform:     data.Platform.ValueString(),
+		RemoteTag:    data.RemoteTag.ValueString(),
+		PushMode:     data.PushMode.ValueString(),
+	}
+	resp.Diagnostics.Append(data.ImageSources.ElementsAs(ctx, &spec.ImageSources, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pushed, warns, errors := r.service.PushImage(ctx, spec)
+	resp.Diagnostics.Append(warns...)
+	resp.Diagnostics.Append(errors...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Pushed image to Daytona registry", map[string]any{
+		"image_name":        data.ImageName.ValueString(),
+		"remote_image_name": pushed.RemoteImageName,
+		"digest":            pushed.Digest,
+	})
+
+	data.RemoteImageName = types.StringValue(pushed.RemoteImageName)
+	data.Digest = types.StringValue(pushed.Digest)
+	data.Id = types.StringValue(fmt.Sprintf("%s@%s", pushed.RemoteImageName, pushed.Digest))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryImageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// the transient registry credentials only allow pushing, so the pushed
+	// image can't be looked up again and the state is kept as is
+}
+
+func (r *RegistryImageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *RegistryImageResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// only image sourcing settings can change in place, and they only matter
+	// when pushing
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RegistryImageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *RegistryImageResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Removing registry image from state, the image is kept in the registry", map[string]any{
+		"remote_image_name": data.RemoteImageName.ValueString(),
+	})
+}
@@ -0,0 +1,223 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ resource.Resource = &RoleResource{}
+var _ resource.ResourceWithImportState = &RoleResource{}
+
+func NewRoleResource() resource.Resource {
+	return &RoleResource{}
+}
+
+type RoleResource struct {
+	client *daytona.Client
+}
+
+type RoleResourceModel struct {
+	Id          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Permissions types.Set    `tfsdk:"permissions"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+}
+
+func (r *RoleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role"
+}
+
+func (r *RoleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a custom role of the provider's organization. Roles are assigned to members with `daytona_organization_role_assignment`",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the role",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the role",
+				Required:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "The description of the role",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+			},
+			"permissions": schema.SetAttribute{
+				MarkdownDescription: "Permissions granted by the role, e.g. `write:sandboxes`",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The creation timestamp of the role",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *RoleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *RoleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *RoleResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var permissions []string
+	resp.Diagnostics.Append(data.Permissions.ElementsAs(ctx, &permissions, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createRequest := apiclient.NewCreateOrganizationRole(data.Name.ValueString(), data.Description.ValueString(), permissions)
+
+	role, httpResp, err := r.client.OrganizationsAPI.CreateOrganizationRole(ctx, r.client.OrganizationID).CreateOrganizationRole(*createRequest).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create role, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRole(ctx, role)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *RoleResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// there is no lookup of a single role
+	roles, httpResp, err := r.client.OrganizationsAPI.ListOrganizationRoles(ctx, r.client.OrganizationID).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list roles, got error: %s", err))
+		return
+	}
+
+	for i := range roles {
+		if roles[i].Id == data.Id.ValueString() {
+			resp.Diagnostics.Append(data.setRole(ctx, &roles[i])...)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+	}
+
+	tflog.Info(ctx, "Role no longer exists, removing it from state", map[string]any{
+		"role_id": data.Id.ValueString(),
+	})
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *RoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *RoleResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var permissions []string
+	resp.Diagnostics.Append(data.Permissions.ElementsAs(ctx, &permissions, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateRequest := apiclient.NewUpdateOrganizationRole(data.Name.ValueString(), data.Description.ValueString(), permissions)
+
+	role, httpResp, err := r.client.OrganizationsAPI.UpdateOrganizationRole(ctx, r.client.OrganizationID, data.Id.ValueString()).UpdateOrganizationRole(*updateRequest).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update role, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(data.setRole(ctx, role)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RoleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *RoleResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	httpResp, err := r.client.OrganizationsAPI.DeleteOrganizationRole(ctx, r.client.OrganizationID, data.Id.ValueString()).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil && (httpResp == nil || httpResp.StatusCode != 404) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete role, got error: %s", err))
+	}
+}
+
+func (r *RoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// setRole fills in the attributes reported by the API.
+func (m *RoleResourceModel) setRole(ctx context.Context, role *apiclient.OrganizationRole) (diags diag.Diagnostics) {
+	permissions, diags := types.SetValueFrom(ctx, types.StringType, role.Permissions)
+	if diags.HasError() {
+		return
+	}
+
+	m.Id = types.StringValue(role.Id)
+	m.Name = types.StringValue(role.Name)
+	m.Description = types.StringValue(role.Description)
+	m.Permissions = permissions
+	m.CreatedAt = types.StringValue(role.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
+	return
+}
@@ -0,0 +1,259 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ resource.Resource = &SandboxExecResource{}
+
+func NewSandboxExecResource() resource.Resource {
+	return &SandboxExecResource{}
+}
+
+type SandboxExecResource struct {
+	client *daytona.Client
+}
+
+type SandboxExecResourceModel struct {
+	Id              types.String `tfsdk:"id"`
+	SandboxId       types.String `tfsdk:"sandbox_id"`
+	Commands        types.List   `tfsdk:"commands"`
+	DestroyCommands types.List   `tfsdk:"destroy_commands"`
+	Cwd             types.String `tfsdk:"cwd"`
+	Timeout         types.Int32  `tfsdk:"timeout"`
+	Triggers        types.Map    `tfsdk:"triggers"`
+	Outputs         types.List   `tfsdk:"outputs"`
+	ExitCode        types.Int32  `tfsdk:"exit_code"`
+}
+
+func (r *SandboxExecResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sandbox_exec"
+}
+
+func (r *SandboxExecResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runs commands inside a sandbox through the toolbox API when the resource is created, and optionally when it is destroyed. The commands run again whenever `commands`, `cwd` or `triggers` change",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the sandbox the commands ran in",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sandbox_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the sandbox to run the commands in",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"commands": schema.ListAttribute{
+				MarkdownDescription: "Commands to run in order on creation. A command exiting with a non-zero code fails the creation and skips the remaining ones",
+				ElementType:         types.StringType,
+				Required:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"destroy_commands": schema.ListAttribute{
+				MarkdownDescription: "Commands to run in order when the resource is destroyed. Skipped if the sandbox doesn't exist anymore",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"cwd": schema.StringAttribute{
+				MarkdownDescription: "Working directory to run the commands in",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeout": schema.Int32Attribute{
+				MarkdownDescription: "Timeout for each command in seconds",
+				Optional:            true,
+			},
+			"triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that run the commands again when they change",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"outputs": schema.ListAttribute{
+				MarkdownDescription: "The output of each command, in the order of `commands`",
+				ElementType:         types.StringType,
+				Computed:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"exit_code": schema.Int32Attribute{
+				MarkdownDescription: "The exit code of the last command",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SandboxExecResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *SandboxExecResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SandboxExecResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var commands []string
+	resp.Diagnostics.Append(data.Commands.ElementsAs(ctx, &commands, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	outputs, exitCode, diags := r.runCommands(ctx, data, commands)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = data.SandboxId
+	data.ExitCode = types.Int32Value(exitCode)
+	data.Outputs, diags = types.ListValueFrom(ctx, types.StringType, outputs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SandboxExecResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// the commands ran once, there is nothing to refresh
+}
+
+func (r *SandboxExecResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SandboxExecResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// only destroy_commands and timeout can change in place, and they only
+	// matter when commands run
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SandboxExecResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *SandboxExecResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.DestroyCommands.IsNull() {
+		return
+	}
+
+	var commands []string
+	resp.Diagnostics.Append(data.DestroyCommands.ElementsAs(ctx, &commands, false)...)
+	if resp.Diagnostics.HasError() || len(commands) == 0 {
+		return
+	}
+
+	_, httpResp, err := r.client.SandboxAPI.GetSandbox(ctx, data.SandboxId.ValueString()).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		if httpResp != nil && httpResp.StatusCode == 404 {
+			tflog.Info(ctx, "Sandbox no longer exists, skipping destroy commands", map[string]any{
+				"sandbox_id": data.SandboxId.ValueString(),
+			})
+			return
+		}
+
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read sandbox, got error: %s", err))
+		return
+	}
+
+	_, _, diags := r.runCommands(ctx, data, commands)
+	resp.Diagnostics.Append(diags...)
+}
+
+// runCommands runs the commands in order, stopping at the first one that
+// fails.
+func (r *SandboxExecResource) runCommands(ctx context.Context, data *SandboxExecResourceModel, commands []string) (outputs []string, exitCode int32, diags diag.Diagnostics) {
+	sandboxID := data.SandboxId.ValueString()
+	outputs = []string{}
+
+	for _, command := range commands {
+		executeRequest := apiclient.NewExecuteRequest(command)
+		if !data.Cwd.IsNull() {
+			executeRequest.SetCwd(data.Cwd.ValueString())
+		}
+		if !data.Timeout.IsNull() {
+			executeRequest.SetTimeout(float32(data.Timeout.ValueInt32()))
+		}
+
+		tflog.Info(ctx, "Running command in sandbox", map[string]any{
+			"sandbox_id": sandboxID,
+			"command":    command,
+		})
+
+		result, httpResp, err := r.client.ToolboxAPI.ExecuteCommand(ctx, sandboxID).ExecuteRequest(*executeRequest).Execute()
+		if httpResp != nil && httpResp.Body != nil {
+			httpResp.Body.Close()
+		}
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to run command %q in sandbox %s, got error: %s", command, sandboxID, err))
+			return
+		}
+
+		exitCode = int32(result.ExitCode)
+		outputs = append(outputs, result.Result)
+
+		if exitCode != 0 {
+			diags.AddError(
+				"Command Failed",
+				fmt.Sprintf("Command %q exited with code %d: %s", command, exitCode, result.Result),
+			)
+			return
+		}
+	}
+
+	return
+}
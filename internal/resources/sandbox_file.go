@@ -0,0 +1,308 @@
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ resource.Resource = &SandboxFileResource{}
+var _ resource.ResourceWithConfigValidators = &SandboxFileResource{}
+var _ resource.ResourceWithModifyPlan = &SandboxFileResource{}
+
+func NewSandboxFileResource() resource.Resource {
+	return &SandboxFileResource{}
+}
+
+type SandboxFileResource struct {
+	client *daytona.Client
+}
+
+type SandboxFileResourceModel struct {
+	Id            types.String `tfsdk:"id"`
+	SandboxId     types.String `tfsdk:"sandbox_id"`
+	Path          types.String `tfsdk:"path"`
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	Permissions   types.String `tfsdk:"permissions"`
+	Sha256        types.String `tfsdk:"sha256"`
+}
+
+func (r *SandboxFileResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sandbox_file"
+}
+
+func (r *SandboxFileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Uploads a file into a sandbox through the toolbox API. The file is downloaded on refresh and uploaded again if its checksum no longer matches",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The sandbox ID and path of the file, separated by a colon",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sandbox_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the sandbox to upload the file into",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "The absolute path of the file inside the sandbox",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "The contents of the file as a UTF-8 string. Conflicts with `content_base64`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"content_base64": schema.StringAttribute{
+				MarkdownDescription: "The base64-encoded contents of the file, for binary files. Conflicts with `content`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"permissions": schema.StringAttribute{
+				MarkdownDescription: "The octal file mode to set after uploading, e.g. `0600`. Changes made inside the sandbox aren't detected",
+				Optional:            true,
+			},
+			"sha256": schema.StringAttribute{
+				MarkdownDescription: "The SHA-256 checksum of the file contents",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *SandboxFileResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(path.MatchRoot("content"), path.MatchRoot("content_base64")),
+	}
+}
+
+func (r *SandboxFileResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan plans the checksum of the configured contents, so a file
+// changed inside the sandbox shows up as a difference to the refreshed
+// checksum and is uploaded again.
+func (r *SandboxFileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data SandboxFileResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.Content.IsUnknown() || data.ContentBase64.IsUnknown() {
+		return
+	}
+
+	content, diags := data.contents()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sha256"), checksum(content))...)
+}
+
+func (r *SandboxFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SandboxFileResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.upload(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(fmt.Sprintf("%s:%s", data.SandboxId.ValueString(), data.Path.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SandboxFileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SandboxFileResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	file, httpResp, err := r.client.ToolboxAPI.DownloadFile(ctx, data.SandboxId.ValueString()).Path(data.Path.ValueString()).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		if httpResp != nil && httpResp.StatusCode == 404 {
+			tflog.Info(ctx, "File or sandbox no longer exists, removing it from state", map[string]any{
+				"sandbox_id": data.SandboxId.ValueString(),
+				"path":       data.Path.ValueString(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to download file %q, got error: %s", data.Path.ValueString(), err))
+		return
+	}
+
+	// empty files are returned as no file at all
+	var content []byte
+	if file != nil {
+		defer os.Remove(file.Name())
+		defer file.Close()
+
+		content, err = io.ReadAll(file)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read downloaded file %q, got error: %s", data.Path.ValueString(), err))
+			return
+		}
+	}
+
+	data.Sha256 = types.StringValue(checksum(content))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SandboxFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SandboxFileResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.upload(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SandboxFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *SandboxFileResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	httpResp, err := r.client.ToolboxAPI.DeleteFile(ctx, data.SandboxId.ValueString()).Path(data.Path.ValueString()).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil && (httpResp == nil || httpResp.StatusCode != 404) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete file %q, got error: %s", data.Path.ValueString(), err))
+	}
+}
+
+// upload writes the configured contents to the file and applies its
+// permissions.
+func (r *SandboxFileResource) upload(ctx context.Context, data *SandboxFileResourceModel) (diags diag.Diagnostics) {
+	sandboxID := data.SandboxId.ValueString()
+	filePath := data.Path.ValueString()
+
+	content, diags := data.contents()
+	if diags.HasError() {
+		return
+	}
+
+	// the generated client only uploads from files on disk
+	file, err := os.CreateTemp("", "daytona-sandbox-file")
+	if err != nil {
+		diags.AddError("File Error", fmt.Sprintf("Unable to create temporary file: %v", err))
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if _, err = file.Write(content); err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		diags.AddError("File Error", fmt.Sprintf("Unable to write temporary file: %v", err))
+		return
+	}
+
+	httpResp, err := r.client.ToolboxAPI.UploadFile(ctx, sandboxID).Path(filePath).File(file).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to upload file %q into sandbox %s, got error: %s", filePath, sandboxID, err))
+		return
+	}
+
+	if !data.Permissions.IsNull() {
+		httpResp, err = r.client.ToolboxAPI.SetFilePermissions(ctx, sandboxID).Path(filePath).Mode(data.Permissions.ValueString()).Execute()
+		if httpResp != nil && httpResp.Body != nil {
+			httpResp.Body.Close()
+		}
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to set permissions of file %q, got error: %s", filePath, err))
+			return
+		}
+	}
+
+	data.Sha256 = types.StringValue(checksum(content))
+	return
+}
+
+// contents returns the configured file contents, decoding content_base64.
+func (m *SandboxFileResourceModel) contents() (content []byte, diags diag.Diagnostics) {
+	if m.ContentBase64.IsNull() {
+		return []byte(m.Content.ValueString()), diags
+	}
+
+	content, err := base64.StdEncoding.DecodeString(m.ContentBase64.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("content_base64"), "Invalid Attribute Value", fmt.Sprintf("Unable to decode base64 contents: %v", err))
+	}
+	return
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -2,54 +2,86 @@ package resources
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
-	"time"
 
 	"github.com/daytonaio/apiclient"
-	"github.com/docker/docker/api/types/image"
-	"github.com/docker/docker/api/types/registry"
-	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+	"github.com/geldata/terraform-provider-daytona/internal/service"
+	"github.com/geldata/terraform-provider-daytona/internal/validators"
 )
 
 var _ resource.Resource = &SnapshotResource{}
 var _ resource.ResourceWithImportState = &SnapshotResource{}
+var _ resource.ResourceWithConfigValidators = &SnapshotResource{}
+var _ resource.ResourceWithModifyPlan = &SnapshotResource{}
+var _ resource.ResourceWithValidateConfig = &SnapshotResource{}
+
+// defaultSnapshotMemory is the memory in GB of snapshots that don't set it.
+const defaultSnapshotMemory = 1
+
+// minGpuSnapshotMemory is the least memory in GB of snapshots with a GPU,
+// which can't run GPU workloads with less.
+const minGpuSnapshotMemory = 4
 
 func NewSnapshotResource() resource.Resource {
 	return &SnapshotResource{}
 }
 
 type SnapshotResource struct {
-	client *apiclient.APIClient
+	service *service.Service
 }
 
 type SnapshotResourceModel struct {
-	Id              types.String  `tfsdk:"id"`
-	Name            types.String  `tfsdk:"name"`
-	ImageName       types.String  `tfsdk:"image_name"`
-	RemoteImageName types.String  `tfsdk:"remote_image_name"`
-	OrganizationId  types.String  `tfsdk:"organization_id"`
-	Size            types.Float32 `tfsdk:"size"`
-	Cpu             types.Int32   `tfsdk:"cpu"`
-	Gpu             types.Int32   `tfsdk:"gpu"`
-	Memory          types.Int32   `tfsdk:"memory"`
-	Disk            types.Int32   `tfsdk:"disk"`
-	CreatedAt       types.String  `tfsdk:"created_at"`
-	KeepRemotely    types.Bool    `tfsdk:"keep_remotely"`
+	Id                 types.String  `tfsdk:"id"`
+	Name               types.String  `tfsdk:"name"`
+	ImageName          types.String  `tfsdk:"image_name"`
+	ImageSources       types.List    `tfsdk:"image_sources"`
+	ImageArchive       types.String  `tfsdk:"image_archive"`
+	KeepLocalTag       types.Bool    `tfsdk:"keep_local_tag"`
+	Platform           types.String  `tfsdk:"platform"`
+	RemoteTag          types.String  `tfsdk:"remote_tag"`
+	PushMode           types.String  `tfsdk:"push_mode"`
+	BuildContext       types.String  `tfsdk:"build_context"`
+	Dockerfile         types.String  `tfsdk:"dockerfile"`
+	BuildTarget        types.String  `tfsdk:"build_target"`
+	BuildContextHash   types.String  `tfsdk:"build_context_hash"`
+	RemoteImageName    types.String  `tfsdk:"remote_image_name"`
+	Entrypoint         types.List    `tfsdk:"entrypoint"`
+	LocalImageID       types.String  `tfsdk:"local_image_id"`
+	OrganizationId     types.String  `tfsdk:"organization_id"`
+	Size               types.Float32 `tfsdk:"size"`
+	Cpu                types.Int32   `tfsdk:"cpu"`
+	Gpu                types.Int32   `tfsdk:"gpu"`
+	Memory             types.Int32   `tfsdk:"memory"`
+	Disk               types.Int32   `tfsdk:"disk"`
+	CreatedAt          types.String  `tfsdk:"created_at"`
+	State              types.String  `tfsdk:"state"`
+	KeepRemotely       types.Bool    `tfsdk:"keep_remotely"`
+	DestroyBehavior    types.String  `tfsdk:"destroy_behavior"`
+	VerifyOnCreate     types.Bool    `tfsdk:"verify_on_create"`
+	VerifyCommand      types.String  `tfsdk:"verify_command"`
+	WaitForActive      types.Bool    `tfsdk:"wait_for_active"`
+	DeletionProtection types.Bool    `tfsdk:"deletion_protection"`
 }
 
 func (r *SnapshotResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -73,15 +105,131 @@ func (r *SnapshotResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"image_name": schema.StringAttribute{
-				MarkdownDescription: "The local container image name for the snapshot",
-				Required:            true,
+				MarkdownDescription: "The local container image name for the snapshot. Conflicts with `remote_image_name`",
+				Optional:            true,
+				Validators: []validator.String{
+					validators.ImageReferenceOrEmpty(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							// imported snapshots have an empty image_name, setting it
+							// afterwards shouldn't recreate them
+							resp.RequiresReplace = (req.StateValue.IsNull() || req.StateValue.ValueString() != "") &&
+								!service.SameImageReference(req.PlanValue.ValueString(), req.StateValue.ValueString())
+						},
+						"Changing the image name to a different image recreates the snapshot, unless the snapshot was imported",
+						"Changing the image name to a different image recreates the snapshot, unless the snapshot was imported",
+					),
+				},
+			},
+			"image_sources": schema.ListAttribute{
+				MarkdownDescription: "Ordered list of places to source `image_name` from before pushing it: `local` uses the image already present in the Docker daemon, `archive` loads it from the `image_archive` tarball, `registry` pulls it from its remote registry. The first source that provides the image wins. `copy` instead copies it from its remote registry straight into Daytona's registry, without a Docker daemon, and can't be combined with other sources; credentials for the remote registry come from the Docker CLI's config. Defaults to `[\"local\"]`",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				Default:             listdefault.StaticValue(defaultImageSources()),
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(service.ImageSourceLocal, service.ImageSourceArchive, service.ImageSourceRegistry, service.ImageSourceCopy)),
+				},
+			},
+			"image_archive": schema.StringAttribute{
+				MarkdownDescription: "Path to a `docker save` tarball containing `image_name`. Used by the `archive` image source",
+				Optional:            true,
+			},
+			"keep_local_tag": schema.BoolAttribute{
+				MarkdownDescription: "Whether to keep the `remote_image_name` tag in the local Docker daemon after pushing instead of removing it",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Platform of the image to push, as `os/arch[/variant]`, e.g. `linux/amd64`. Selects the platform when building, pulling or copying multi-platform images, and fails the push if the local image was built for another one. " +
+					"Defaults to whatever the Docker daemon or registry provides",
+				Optional: true,
+				Validators: []validator.String{
+					validators.Platform(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							var imageName types.String
+							resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("image_name"), &imageName)...)
+							resp.RequiresReplace = imageName.ValueString() != ""
+						},
+						"Changing the platform recreates the snapshot, unless the snapshot was imported",
+						"Changing the platform recreates the snapshot, unless the snapshot was imported",
+					),
+				},
+			},
+			"remote_tag": schema.StringAttribute{
+				MarkdownDescription: "Tag of the image in Daytona's registry. May use the placeholders `{timestamp}` for the time of the push, `{tag}` for the tag of `image_name` and `{digest}` for the first 12 hex digits of the local image's digest, which copied images only have when `image_name` is pinned by digest. " +
+					"A git SHA or other values can be passed in through variables, e.g. `\"${var.git_sha}\"`. Changing it only affects later pushes. " +
+					"Retrying a failed creation doesn't push the same image again, as it's looked up by its tag or, with `{timestamp}`, among the newest tags of its repository in Daytona's registry. Defaults to `{timestamp}`",
+				Optional: true,
+				Validators: []validator.String{
+					validators.TagTemplate(service.RemoteTagPlaceholders...),
+				},
+			},
+			"push_mode": pushModeAttribute(),
+			"build_context": schema.StringAttribute{
+				MarkdownDescription: "Directory to build `image_name` from with the local Docker daemon before pushing it, instead of sourcing it through `image_sources`. " +
+					"Files excluded by its `.dockerignore` aren't sent to the daemon. The image is rebuilt and the snapshot recreated whenever the other files change. Requires `image_name`",
+				Optional: true,
+			},
+			"dockerfile": schema.StringAttribute{
+				MarkdownDescription: "Path of the Dockerfile within `build_context`. Defaults to `Dockerfile`",
+				Optional:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"build_target": schema.StringAttribute{
+				MarkdownDescription: "Stage of a multi-stage Dockerfile to build. Defaults to the last stage",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"build_context_hash": schema.StringAttribute{
+				MarkdownDescription: "Hash of the files of `build_context` that the image was built from",
+				Computed:            true,
+			},
 			"remote_image_name": schema.StringAttribute{
-				MarkdownDescription: "The remote image name in Daytona's registry",
+				MarkdownDescription: "The remote image name in Daytona's registry. Set it instead of `image_name` to register the snapshot from an already pushed image, e.g. one managed by `daytona_registry_image`",
+				Optional:            true,
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							resp.RequiresReplace = !req.ConfigValue.IsNull() &&
+								!service.SameImageReference(req.PlanValue.ValueString(), req.StateValue.ValueString())
+						},
+						"Changing a configured remote image name to a different image recreates the snapshot",
+						"Changing a configured remote image name to a different image recreates the snapshot",
+					),
+				},
+			},
+			"local_image_id": schema.StringAttribute{
+				MarkdownDescription: "Content digest of the local image that was pushed, as reported by the Docker daemon. When `image_name` is rebuilt under the same tag, the new digest plans a replacement of the snapshot. " +
+					"Null for snapshots registered from `remote_image_name`, copied images and imported snapshots",
+				Computed: true,
+			},
+			"entrypoint": schema.ListAttribute{
+				MarkdownDescription: "Command that sandboxes created from the snapshot run, overriding the image's default entrypoint",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+							var imageName types.String
+							resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("image_name"), &imageName)...)
+							resp.RequiresReplace = imageName.IsNull() || imageName.ValueString() != ""
+						},
+						"Changing the entrypoint recreates the snapshot, unless the snapshot was imported",
+						"Changing the entrypoint recreates the snapshot, unless the snapshot was imported",
+					),
+				},
 			},
 			"organization_id": schema.StringAttribute{
 				MarkdownDescription: "The organization ID for the snapshot",
@@ -92,32 +240,49 @@ func (r *SnapshotResource) Schema(ctx context.Context, req resource.SchemaReques
 				Computed:            true,
 			},
 			"cpu": schema.Int32Attribute{
-				MarkdownDescription: "CPU cores allocated to the resulting sandbox",
+				MarkdownDescription: "CPU cores allocated to the resulting sandbox. Plans fail when it exceeds the per-sandbox limit of the organization's tier",
 				Optional:            true,
 				Computed:            true,
 				Default:             int32default.StaticInt32(1),
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
 				PlanModifiers: []planmodifier.Int32{
 					int32planmodifier.RequiresReplace(),
 				},
 			},
 			"gpu": schema.Int32Attribute{
 				MarkdownDescription: "GPU units allocated to the resulting sandbox",
+				Optional:            true,
 				Computed:            true,
+				Default:             int32default.StaticInt32(0),
+				Validators: []validator.Int32{
+					int32validator.AtLeast(0),
+				},
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.RequiresReplace(),
+				},
 			},
 			"memory": schema.Int32Attribute{
-				MarkdownDescription: "Memory allocated to the resulting sandbox in GB",
+				MarkdownDescription: "Memory allocated to the resulting sandbox in GB, at least 4 with a `gpu`. Plans fail when it exceeds the per-sandbox limit of the organization's tier",
 				Optional:            true,
 				Computed:            true,
-				Default:             int32default.StaticInt32(1),
+				Default:             int32default.StaticInt32(defaultSnapshotMemory),
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
 				PlanModifiers: []planmodifier.Int32{
 					int32planmodifier.RequiresReplace(),
 				},
 			},
 			"disk": schema.Int32Attribute{
-				MarkdownDescription: "Disk space allocated to the resulting sandbox in GB",
+				MarkdownDescription: "Disk space allocated to the resulting sandbox in GB. Plans fail when it exceeds the per-sandbox limit of the organization's tier",
 				Optional:            true,
 				Computed:            true,
 				Default:             int32default.StaticInt32(3),
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
 				PlanModifiers: []planmodifier.Int32{
 					int32planmodifier.RequiresReplace(),
 				},
@@ -126,8 +291,30 @@ func (r *SnapshotResource) Schema(ctx context.Context, req resource.SchemaReques
 				MarkdownDescription: "The creation timestamp of the snapshot",
 				Computed:            true,
 			},
-			"keep_remotely": schema.BoolAttribute{
-				MarkdownDescription: "Whether to keep the snapshot in Daytona when the Terraform resource is destroyed",
+			"state": schema.StringAttribute{
+				MarkdownDescription: "The state of the snapshot, e.g. `pending`, `active` or `error`. Refreshed on every read, so snapshots created with `wait_for_active = false` can be checked later",
+				Computed:            true,
+			},
+			"keep_remotely":    keepRemotelyAttribute(),
+			"destroy_behavior": destroyBehaviorAttribute(),
+			"verify_on_create": schema.BoolAttribute{
+				MarkdownDescription: "Whether to start a temporary sandbox from the snapshot after it becomes active, failing the creation if the sandbox can't start",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"verify_command": schema.StringAttribute{
+				MarkdownDescription: "Health-check command to run inside the temporary verification sandbox. A non-zero exit code fails the creation. Only used when `verify_on_create` is enabled",
+				Optional:            true,
+			},
+			"wait_for_active": schema.BoolAttribute{
+				MarkdownDescription: "Whether creating the snapshot waits for Daytona to process it until it is active. When `false`, the creation finishes once the snapshot is registered, and failures only show in `state`. Conflicts with `verify_on_create`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Whether destroying or replacing the snapshot fails, whatever its `destroy_behavior`. Has to be set to `false` and applied before the snapshot can be destroyed or replaced",
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
@@ -136,21 +323,81 @@ func (r *SnapshotResource) Schema(ctx context.Context, req resource.SchemaReques
 	}
 }
 
+func (r *SnapshotResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(path.MatchRoot("image_name"), path.MatchRoot("remote_image_name")),
+	}
+}
+
+func (r *SnapshotResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data SnapshotResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateDestroyBehavior(data.KeepRemotely, data.DestroyBehavior)...)
+
+	if !data.BuildContext.IsNull() && data.ImageName.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("build_context"),
+			"Missing Image Name",
+			"build_context requires image_name, which the built image is tagged as.",
+		)
+	}
+
+	memory := data.Memory
+	if memory.IsNull() {
+		memory = types.Int32Value(defaultSnapshotMemory)
+	}
+	if data.Gpu.ValueInt32() > 0 && !memory.IsUnknown() && memory.ValueInt32() < minGpuSnapshotMemory {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("memory"),
+			"Insufficient Memory For GPU",
+			fmt.Sprintf("Snapshots with a GPU need at least %d GB of memory, got: %d", minGpuSnapshotMemory, memory.ValueInt32()),
+		)
+	}
+
+	if data.VerifyOnCreate.ValueBool() && !data.WaitForActive.IsNull() && !data.WaitForActive.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("verify_on_create"),
+			"Conflicting Wait Configuration",
+			"verify_on_create needs an active snapshot to start the sandbox from, it can't be combined with wait_for_active = false.",
+		)
+	}
+
+	for _, attribute := range []struct {
+		name  string
+		value types.String
+	}{
+		{"dockerfile", data.Dockerfile},
+		{"build_target", data.BuildTarget},
+	} {
+		if !attribute.value.IsNull() && data.BuildContext.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute.name),
+				"Missing Build Context",
+				fmt.Sprintf("%s is only used when building from build_context.", attribute.name),
+			)
+		}
+	}
+}
+
 func (r *SnapshotResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*apiclient.APIClient)
+	client, ok := req.ProviderData.(*daytona.Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *apiclient.APIClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.service = service.New(client)
 }
 
 func (r *SnapshotResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -161,482 +408,435 @@ func (r *SnapshotResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	infos, warns, errors := r.createSnapshot(ctx, data)
-	resp.Diagnostics.Append(infos...)
+	spec, diags := data.snapshotSpec(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	snapshot, pushed, warns, errors := r.service.CreateSnapshot(ctx, spec)
 	resp.Diagnostics.Append(warns...)
 	resp.Diagnostics.Append(errors...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	data.setSnapshot(snapshot)
+	data.setLocalImageID(pushed)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (r *SnapshotResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data *SnapshotResourceModel
-
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
+// ModifyPlan hashes the build context and inspects the local image, planning
+// a replacement when either changed.
+func (r *SnapshotResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
 		return
 	}
 
-	infoDiags, warnDiags, errDiags := r.readSnapshot(ctx, data)
-	resp.Diagnostics.Append(infoDiags...)
-	resp.Diagnostics.Append(warnDiags...)
-	resp.Diagnostics.Append(errDiags...)
+	resp.Diagnostics.Append(r.planBuildContextHash(ctx, req, resp)...)
+	resp.Diagnostics.Append(r.planLocalImageID(ctx, req, resp)...)
+	resp.Diagnostics.Append(r.checkSandboxLimits(ctx, req)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(checkReplacementName(ctx, req, resp)...)
 }
 
-func (r *SnapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data *SnapshotResourceModel
-	var stateData SnapshotResourceModel
-
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-	resp.Diagnostics.Append(req.State.Get(ctx, &stateData)...)
-	if resp.Diagnostics.HasError() {
+// checkReplacementName fails plans replacing a snapshot that destroy_behavior
+// keeps with one of the same name. Snapshot names are unique, so creating the
+// replacement would delete the snapshot that should be kept. The prior
+// state's behavior applies when Terraform destroys the snapshot, the planned
+// one when Update replaces it.
+func checkReplacementName(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) (diags diag.Diagnostics) {
+	if req.State.Raw.IsNull() {
 		return
 	}
 
-	shouldRecreate :=
-		// recreate if image_name changes, except when importing (state has empty image_name)
-		(!data.ImageName.Equal(stateData.ImageName) &&
-			!(stateData.ImageName.ValueString() == "" && data.ImageName.ValueString() != "")) ||
-			!data.Name.Equal(stateData.Name) ||
-			!data.Cpu.Equal(stateData.Cpu) ||
-			!data.Memory.Equal(stateData.Memory) ||
-			!data.Disk.Equal(stateData.Disk)
-
-	if shouldRecreate {
-		if !data.KeepRemotely.ValueBool() {
-			infos, warns, errors := r.deleteSnapshot(ctx, &stateData)
-			resp.Diagnostics.Append(infos...)
-			resp.Diagnostics.Append(warns...)
-			resp.Diagnostics.Append(errors...)
-			if resp.Diagnostics.HasError() {
-				return
-			}
-		} else {
-			tflog.Info(ctx, "Skipping old snapshot deletion during recreation due to keep_remotely=true", map[string]interface{}{
-				"old_snapshot_id":   stateData.Id.ValueString(),
-				"old_snapshot_name": stateData.Name.ValueString(),
-			})
-		}
-
-		infos, warns, errors := r.createSnapshot(ctx, data)
-		resp.Diagnostics.Append(infos...)
-		resp.Diagnostics.Append(warns...)
-		resp.Diagnostics.Append(errors...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-
-		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-	} else {
-		if data.Id.IsUnknown() {
-			data.Id = stateData.Id
-		}
+	var data, state SnapshotResourceModel
+	diags.Append(resp.Plan.Get(ctx, &data)...)
+	diags.Append(req.State.Get(ctx, &state)...)
+	if diags.HasError() || data.Name.IsUnknown() || data.Name.ValueString() != state.Name.ValueString() ||
+		data.KeepRemotely.IsUnknown() || data.DestroyBehavior.IsUnknown() {
+		return
+	}
 
-		if data.Name.IsUnknown() {
-			data.Name = stateData.Name
-		}
+	behavior := destroyBehavior(state.KeepRemotely, state.DestroyBehavior)
+	if behavior == service.DestroyBehaviorDelete {
+		behavior = destroyBehavior(data.KeepRemotely, data.DestroyBehavior)
+	}
+	if behavior == service.DestroyBehaviorDelete {
+		return
+	}
 
-		infos, warns, errors := r.readSnapshot(ctx, data)
-		resp.Diagnostics.Append(infos...)
-		resp.Diagnostics.Append(warns...)
-		resp.Diagnostics.Append(errors...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
+	spec, specDiags := data.snapshotSpec(ctx)
+	diags.Append(specDiags...)
+	stateSpec, specDiags := state.snapshotSpec(ctx)
+	diags.Append(specDiags...)
+	if diags.HasError() {
+		return
+	}
 
-		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	// unknown values plan a replacement as well
+	if len(resp.RequiresReplace) > 0 || spec.RequiresRecreate(stateSpec) {
+		diags.Append(service.ReplacementNameConflict(data.Name.ValueString(), behavior))
 	}
+	return
 }
 
-func (r *SnapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data *SnapshotResourceModel
+// checkSandboxLimits fails plans creating snapshots with more resources than
+// the organization allows a sandbox, which depends on its tier. Limits that
+// can't be read, e.g. with API keys lacking the permission, aren't checked.
+func (r *SnapshotResource) checkSandboxLimits(ctx context.Context, req resource.ModifyPlanRequest) (diags diag.Diagnostics) {
+	if r.service == nil {
+		return
+	}
 
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
+	var data SnapshotResourceModel
+	diags.Append(req.Plan.Get(ctx, &data)...)
+	if diags.HasError() || data.Cpu.IsUnknown() || data.Memory.IsUnknown() || data.Disk.IsUnknown() {
 		return
 	}
+	if !req.State.Raw.IsNull() {
+		var state SnapshotResourceModel
+		diags.Append(req.State.Get(ctx, &state)...)
+		if diags.HasError() || data.Cpu.Equal(state.Cpu) && data.Memory.Equal(state.Memory) && data.Disk.Equal(state.Disk) {
+			return
+		}
+	}
 
-	if data.KeepRemotely.ValueBool() {
-		tflog.Info(ctx, "Skipping snapshot deletion due to keep_remotely=true", map[string]interface{}{
-			"snapshot_id":   data.Id.ValueString(),
-			"snapshot_name": data.Name.ValueString(),
+	limits, err := r.service.SandboxLimits(ctx)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to read the sandbox limits of the organization", map[string]any{
+			"error": err.Error(),
 		})
 		return
 	}
 
-	infos, warns, errors := r.deleteSnapshot(ctx, data)
-
-	resp.Diagnostics.Append(infos...)
-	resp.Diagnostics.Append(warns...)
-	resp.Diagnostics.Append(errors...)
+	if exceeded := limits.Exceeded(data.Cpu.ValueInt32(), data.Memory.ValueInt32(), data.Disk.ValueInt32()); len(exceeded) > 0 {
+		diags.AddError(
+			"Sandbox Limits Exceeded",
+			fmt.Sprintf("Sandboxes can't be created from snapshot %q, the organization's tier doesn't allow its resources:\n%s", data.Name.ValueString(), strings.Join(exceeded, "\n")),
+		)
+	}
+	return
 }
 
-func (r *SnapshotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	snapshotID := req.ID
-
-	snapshot, httpResp, err := r.client.SnapshotsAPI.GetSnapshot(ctx, snapshotID).Execute()
-	if httpResp != nil && httpResp.Body != nil {
-		defer httpResp.Body.Close()
-	}
-	if err != nil && httpResp != nil && httpResp.StatusCode == 404 {
-		resp.Diagnostics.AddError(
-			"Snapshot Not Found",
-			fmt.Sprintf("Snapshot with ID/name %q not found", snapshotID),
-		)
-		return
-	} else if err != nil {
-		resp.Diagnostics.AddError(
-			"Import Error",
-			fmt.Sprintf("Unable to fetch snapshot %q: %v", snapshotID, err),
-		)
+// planLocalImageID compares the local image with the one that was pushed,
+// planning a replacement when image_name was rebuilt under the same tag.
+// Built images are covered by their build context hash instead. Images
+// missing from the Docker daemon, e.g. on machines that only plan, are
+// assumed unchanged.
+func (r *SnapshotResource) planLocalImageID(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) (diags diag.Diagnostics) {
+	if r.service == nil || req.State.Raw.IsNull() {
 		return
 	}
 
-	data := &SnapshotResourceModel{
-		Id:              types.StringValue(snapshot.Id),
-		Name:            types.StringValue(snapshot.Name),
-		Cpu:             types.Int32Value(int32(snapshot.Cpu)),
-		Gpu:             types.Int32Value(int32(snapshot.Gpu)),
-		Memory:          types.Int32Value(int32(snapshot.Mem)),
-		Disk:            types.Int32Value(int32(snapshot.Disk)),
-		CreatedAt:       types.StringValue(snapshot.CreatedAt.Format("2006-01-02T15:04:05Z07:00")),
-		OrganizationId:  types.StringPointerValue(snapshot.OrganizationId),
-		Size:            types.Float32PointerValue(snapshot.Size.Get()),
-		RemoteImageName: types.StringPointerValue(snapshot.ImageName),
-		KeepRemotely:    types.BoolValue(false),
-
-		// for now image_name is local only and we don't know it from the import...
-		//
-		// probably it would be better to add support for remote registry proxy for ECR
-		// to fix this properly, but this works as a temporary hack as well
-		ImageName: types.StringValue(""),
+	var data, state SnapshotResourceModel
+	diags.Append(req.Plan.Get(ctx, &data)...)
+	diags.Append(req.State.Get(ctx, &state)...)
+	if diags.HasError() {
+		return
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
-}
-
-func (r *SnapshotResource) createSnapshot(ctx context.Context, data *SnapshotResourceModel) (infos, warns, errs diag.Diagnostics) {
-	warnings, errors := r.maybeCleanupExistingCreationAttempt(ctx, data.Name.ValueString())
-	warns.Append(warnings...)
-	errs.Append(errors...)
-	if errs.HasError() {
+	if state.LocalImageID.IsNull() || data.ImageName.IsUnknown() || !data.BuildContext.IsNull() ||
+		!service.SameImageReference(data.ImageName.ValueString(), state.ImageName.ValueString()) {
 		return
 	}
 
-	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	localImageID, err := r.service.LocalImageID(ctx, data.ImageName.ValueString())
 	if err != nil {
-		errs.AddError("Docker Client Error", fmt.Sprintf("Unable to create Docker client: %v", err))
+		tflog.Debug(ctx, "Unable to inspect the local image, assuming it is unchanged", map[string]any{
+			"image_name": data.ImageName.ValueString(),
+			"error":      err.Error(),
+		})
+		return
+	}
+	if localImageID == state.LocalImageID.ValueString() {
 		return
 	}
-	defer dockerClient.Close()
 
-	targetImage, warnings, errors := r.pushImageToRegistry(ctx, dockerClient, data.ImageName.ValueString())
-	warns.Append(warnings...)
-	errs.Append(errors...)
-	if errs.HasError() {
+	tflog.Info(ctx, "Local image changed since it was pushed, replacing the snapshot", map[string]any{
+		"image_name":     data.ImageName.ValueString(),
+		"local_image_id": localImageID,
+	})
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("local_image_id"), types.StringUnknown())...)
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("local_image_id"))
+	return
+}
+
+// planBuildContextHash plans the hash of the build context's current files.
+// A changed hash replaces the snapshot, except for imported snapshots, which
+// aren't known to have been built from the context before.
+func (r *SnapshotResource) planBuildContextHash(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) (diags diag.Diagnostics) {
+	var data SnapshotResourceModel
+	diags.Append(req.Plan.Get(ctx, &data)...)
+	if diags.HasError() || data.BuildContext.IsUnknown() || data.Dockerfile.IsUnknown() {
 		return
 	}
 
-	// we don't care too much about untagging. it's a garbage left behind, but not
-	// a real error that prevents us from continuing
-	defer func() {
-		_, err = dockerClient.ImageRemove(ctx, targetImage, image.RemoveOptions{})
+	hash := types.StringNull()
+	if !data.BuildContext.IsNull() {
+		value, err := service.BuildContextHash(*data.buildSpec())
 		if err != nil {
-			warnings.AddWarning("Cleanup Warning", fmt.Sprintf("Failed to remove tagged image %s: %v", targetImage, err))
+			diags.AddAttributeError(
+				path.Root("build_context"),
+				"Build Context Error",
+				fmt.Sprintf("Unable to hash build context %q: %v", data.BuildContext.ValueString(), err),
+			)
+			return
 		}
-	}()
+		hash = types.StringValue(value)
+	}
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("build_context_hash"), hash)...)
 
-	warnings, errors = r.registerSnapshot(ctx, data, targetImage)
-	warns.Append(warnings...)
-	errs.Append(errors...)
-	if errs.HasError() {
+	if req.State.Raw.IsNull() {
 		return
 	}
 
-	snapshot, warnings, errors := r.ensureSnapshotAvailable(ctx, data.Name.ValueString())
-	warns.Append(warnings...)
-	errs.Append(errors...)
-	if errs.HasError() {
+	var state SnapshotResourceModel
+	diags.Append(req.State.Get(ctx, &state)...)
+	if diags.HasError() {
 		return
 	}
 
-	data.Id = types.StringValue(snapshot.Id)
-	data.Name = types.StringValue(snapshot.Name)
-	data.Cpu = types.Int32Value(int32(snapshot.Cpu))
-	data.Gpu = types.Int32Value(int32(snapshot.Gpu))
-	data.Memory = types.Int32Value(int32(snapshot.Mem))
-	data.Disk = types.Int32Value(int32(snapshot.Disk))
-	data.CreatedAt = types.StringValue(snapshot.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
-
-	if snapshot.OrganizationId != nil {
-		data.OrganizationId = types.StringPointerValue(snapshot.OrganizationId)
+	if !hash.Equal(state.BuildContextHash) && state.ImageName.ValueString() != "" {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("build_context_hash"))
 	}
-	if snapshot.ImageName != nil {
-		data.RemoteImageName = types.StringPointerValue(snapshot.ImageName)
-	}
-	if snapshot.Size.IsSet() {
-		data.Size = types.Float32PointerValue(snapshot.Size.Get())
-	}
-
 	return
 }
 
-func (r *SnapshotResource) maybeCleanupExistingCreationAttempt(ctx context.Context, snapshotName string) (warns, errors diag.Diagnostics) {
-	existingSnapshot, httpResp, err := r.client.SnapshotsAPI.GetSnapshot(ctx, snapshotName).Execute()
-	if httpResp != nil && httpResp.Body != nil {
-		httpResp.Body.Close()
-	}
-	if err != nil && httpResp != nil && httpResp.StatusCode == 404 {
-		return
-	} else if err != nil {
-		errors.AddError("Snapshot Check", fmt.Sprintf("Unable to check for if snapshot exists: %v", err))
+func (r *SnapshotResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SnapshotResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	tflog.Info(ctx, "Found existing snapshot, deleting it", map[string]any{
-		"snapshot_id":    existingSnapshot.Id,
-		"snapshot_name":  existingSnapshot.Name,
-		"snapshot_state": string(existingSnapshot.State),
-	})
-
-	_, err = r.client.SnapshotsAPI.RemoveSnapshot(ctx, existingSnapshot.Id).Execute()
-	if err != nil {
-		warns.AddWarning("Cleanup Warning", fmt.Sprintf("Failed to delete existing failed snapshot %q: %v", snapshotName, err))
+	snapshot, errors := r.service.GetSnapshot(ctx, data.Id.ValueString())
+	resp.Diagnostics.Append(errors...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			_, httpResp, err := r.client.SnapshotsAPI.GetSnapshot(ctx, existingSnapshot.Id).Execute()
-			if httpResp != nil && httpResp.Body != nil {
-				httpResp.Body.Close()
-			}
-			if err != nil && httpResp != nil && httpResp.StatusCode == 404 {
-				tflog.Info(ctx, "Snapshot successfully deleted")
-				return
-			}
-			time.Sleep(time.Second)
-		}
+	if snapshot != nil {
+		data.setSnapshot(snapshot)
 	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (r *SnapshotResource) pushImageToRegistry(ctx context.Context, dockerClient *client.Client, localImageName string) (targetImage string, warns, errors diag.Diagnostics) {
-	tokenResponse, httpResp, err := r.client.DockerRegistryAPI.GetTransientPushAccess(ctx).Execute()
-	if httpResp != nil && httpResp.Body != nil {
-		httpResp.Body.Close()
-	}
-	if err != nil {
-		errors.AddError("API Error", fmt.Sprintf("Unable to get push access token: %v", err))
-		return
-	}
+func (r *SnapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SnapshotResourceModel
+	var stateData SnapshotResourceModel
 
-	encodedAuth, err := json.Marshal(registry.AuthConfig{
-		Username:      tokenResponse.Username,
-		Password:      tokenResponse.Secret,
-		ServerAddress: tokenResponse.RegistryUrl,
-	})
-	if err != nil {
-		errors.AddError("Auth Error", fmt.Sprintf("Unable to encode docker auth config: %v", err))
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &stateData)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_, _, err = dockerClient.ImageInspectWithRaw(ctx, localImageName)
-	if err != nil {
-		errors.AddError("Image Not Found", fmt.Sprintf("Local image %q not found: %v", localImageName, err))
+	spec, diags := data.snapshotSpec(ctx)
+	resp.Diagnostics.Append(diags...)
+	stateSpec, diags := stateData.snapshotSpec(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	localImageParts := strings.Split(localImageName, ":")
-	localImageRepo := localImageParts[0]
-	repoParts := strings.Split(localImageRepo, "/")
-	imageName := repoParts[len(repoParts)-1]
-	timestamp := time.Now().Format("20060102150405")
-	targetImage = fmt.Sprintf("%s/%s/%s:%s", tokenResponse.RegistryUrl, tokenResponse.Project, imageName, timestamp)
+	if spec.RequiresRecreate(stateSpec) {
+		resp.Diagnostics.Append(checkDeletionProtection(stateData)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 
-	err = dockerClient.ImageTag(ctx, localImageName, targetImage)
-	if err != nil {
-		errors.AddError("Tag Error", fmt.Sprintf("Unable to tag image: %v", err))
-		return
-	}
+		snapshot, pushed, warns, errors := r.service.ReplaceSnapshot(ctx, stateData.Id.ValueString(), stateData.Name.ValueString(), destroyBehavior(data.KeepRemotely, data.DestroyBehavior), spec)
+		resp.Diagnostics.Append(warns...)
+		resp.Diagnostics.Append(errors...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 
-	pushReader, err := dockerClient.ImagePush(ctx, targetImage, image.PushOptions{
-		RegistryAuth: base64.URLEncoding.EncodeToString(encodedAuth),
-	})
-	if err != nil {
-		errors.AddError("Push Error", fmt.Sprintf("Unable to push image: %v", err))
-		return
-	}
-	defer pushReader.Close()
+		data.setSnapshot(snapshot)
+		data.setLocalImageID(pushed)
+	} else {
+		data.LocalImageID = stateData.LocalImageID
 
-	_, err = io.Copy(io.Discard, pushReader)
-	if err != nil {
-		errors.AddError("Push Error", fmt.Sprintf("Error during image push: %v", err))
-		return
-	}
+		if data.Id.IsUnknown() {
+			data.Id = stateData.Id
+		}
 
-	for {
-		select {
-		case <-ctx.Done():
-			errors.AddError("Image Availability Error", fmt.Sprintf("Cancelled during waiting for image to become available: %v", ctx.Err()))
-			return
-		default:
-			_, err = dockerClient.DistributionInspect(ctx, targetImage, base64.URLEncoding.EncodeToString(encodedAuth))
+		if data.Name.IsUnknown() {
+			data.Name = stateData.Name
 		}
 
-		if err == nil {
-			break
+		snapshot, errors := r.service.GetSnapshot(ctx, data.Id.ValueString())
+		resp.Diagnostics.Append(errors...)
+		if resp.Diagnostics.HasError() {
+			return
 		}
 
-		tflog.Info(ctx, "Waiting for the image to become available")
-		time.Sleep(time.Second)
+		if snapshot != nil {
+			data.setSnapshot(snapshot)
+		}
 	}
 
-	return
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (r *SnapshotResource) registerSnapshot(ctx context.Context, data *SnapshotResourceModel, targetImage string) (warns, errors diag.Diagnostics) {
-	createRequest := apiclient.NewCreateSnapshot(data.Name.ValueString())
-	createRequest.SetImageName(targetImage)
-
-	if !data.Cpu.IsNull() {
-		cpu := data.Cpu.ValueInt32()
-		createRequest.Cpu = &cpu
-	}
+func (r *SnapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *SnapshotResourceModel
 
-	if !data.Memory.IsNull() {
-		memory := data.Memory.ValueInt32()
-		createRequest.Memory = &memory
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if !data.Disk.IsNull() {
-		disk := data.Disk.ValueInt32()
-		createRequest.Disk = &disk
+	// a state without an ID has no snapshot to delete
+	if data.Id.IsNull() || data.Id.ValueString() == "" {
+		return
 	}
 
-	_, resp, err := r.client.SnapshotsAPI.CreateSnapshot(ctx).CreateSnapshot(*createRequest).Execute()
-	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
-	}
-	if err != nil {
-		errors.AddError("Client Error", fmt.Sprintf("Unable to create snapshot, got error: %v", err))
+	resp.Diagnostics.Append(checkDeletionProtection(*data)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	return
+	resp.Diagnostics.Append(r.service.DestroySnapshot(ctx, data.Id.ValueString(), data.Name.ValueString(), destroyBehavior(data.KeepRemotely, data.DestroyBehavior))...)
 }
 
-func (r *SnapshotResource) ensureSnapshotAvailable(ctx context.Context, snapshotName string) (snapshot *apiclient.SnapshotDto, warns, errs diag.Diagnostics) {
-	for {
-		select {
-		case <-ctx.Done():
-			errs.AddError("Snapshot Availability Error", fmt.Sprintf("Cancelled during waiting for snapshot to become available: %v", ctx.Err()))
-			return nil, warns, errs
-		default:
-			var resp *http.Response
-			var err error
-			snapshot, resp, err = r.client.SnapshotsAPI.GetSnapshot(ctx, snapshotName).Execute()
-			if resp != nil && resp.Body != nil {
-				defer resp.Body.Close()
-			}
-			if err != nil {
-				errs.AddError("Snapshot Availability Error", fmt.Sprintf("Unable to fetch snapshot: %v", err))
-				return
-			}
-
-			switch snapshot.State {
-			case apiclient.SNAPSHOTSTATE_ACTIVE:
-				return
-			case apiclient.SNAPSHOTSTATE_ERROR, apiclient.SNAPSHOTSTATE_BUILD_FAILED:
-				if !snapshot.ErrorReason.IsSet() {
-					errs.AddError("Snapshot Availability Error", "Snapshot processing failed with unknown reason")
-				} else {
-					errs.AddError("Snapshot Availability Error", fmt.Sprintf("Snapshot processing failed: %s", *snapshot.ErrorReason.Get()))
-				}
-				return
-			}
-		}
-
-		tflog.Info(ctx, "Waiting for the snapshot to be processed")
-		time.Sleep(time.Second)
+// checkDeletionProtection fails when the snapshot is protected from being
+// destroyed. The state's setting applies, so disabling the protection takes
+// effect once it is applied.
+func checkDeletionProtection(state SnapshotResourceModel) (diags diag.Diagnostics) {
+	if state.DeletionProtection.ValueBool() {
+		diags.AddError(
+			"Snapshot Deletion Protected",
+			fmt.Sprintf("Snapshot %q can't be destroyed or replaced while deletion_protection is enabled. Set deletion_protection = false and apply first.", state.Name.ValueString()),
+		)
 	}
+	return
 }
 
-func (r *SnapshotResource) readSnapshot(ctx context.Context, data *SnapshotResourceModel) (infos, warns, errors diag.Diagnostics) {
-	snapshot, resp, err := r.client.SnapshotsAPI.GetSnapshot(ctx, data.Id.ValueString()).Execute()
-	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
-	}
-	if err != nil && resp != nil && resp.StatusCode == 404 {
-		return
-	} else if err != nil {
-		errors.AddError("Client Error", fmt.Sprintf("Unable to read snapshot: %v", err))
+func (r *SnapshotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	snapshotID := req.ID
+
+	snapshot, errors := r.service.GetSnapshot(ctx, snapshotID)
+	resp.Diagnostics.Append(errors...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	data.Name = types.StringValue(snapshot.Name)
-	data.Cpu = types.Int32Value(int32(snapshot.Cpu))
-	data.Gpu = types.Int32Value(int32(snapshot.Gpu))
-	data.Memory = types.Int32Value(int32(snapshot.Mem))
-	data.Disk = types.Int32Value(int32(snapshot.Disk))
-	data.CreatedAt = types.StringValue(snapshot.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
+	if snapshot == nil {
+		resp.Diagnostics.AddError(
+			"Snapshot Not Found",
+			fmt.Sprintf("Snapshot with ID/name %q not found", snapshotID),
+		)
+		return
+	}
 
-	if snapshot.OrganizationId != nil {
-		data.OrganizationId = types.StringValue(*snapshot.OrganizationId)
+	data := &SnapshotResourceModel{
+		KeepRemotely:       types.BoolValue(false),
+		DestroyBehavior:    types.StringValue(service.DestroyBehaviorDelete),
+		VerifyOnCreate:     types.BoolValue(false),
+		VerifyCommand:      types.StringNull(),
+		WaitForActive:      types.BoolValue(true),
+		DeletionProtection: types.BoolValue(false),
+		ImageSources:       defaultImageSources(),
+		ImageArchive:       types.StringNull(),
+		KeepLocalTag:       types.BoolValue(false),
+		Platform:           types.StringNull(),
+		RemoteTag:          types.StringNull(),
+		PushMode:           types.StringValue(service.PushModeDaemon),
+		BuildContext:       types.StringNull(),
+		Dockerfile:         types.StringNull(),
+		BuildTarget:        types.StringNull(),
+		BuildContextHash:   types.StringNull(),
+		LocalImageID:       types.StringNull(),
+		Entrypoint:         types.ListNull(types.StringType),
+
+		// Daytona only knows the image in its own registry, not the local or
+		// remote image it was pushed or copied from, so image_name is left
+		// empty for the configuration to set without recreating the snapshot
+		ImageName: types.StringValue(""),
 	}
+	data.setSnapshot(snapshot)
 
-	if snapshot.ImageName != nil {
-		data.RemoteImageName = types.StringValue(*snapshot.ImageName)
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+}
+
+// snapshotSpec converts the model into the service's view of the snapshot.
+// Unknown values, like a remote image name that is yet to be pushed, are
+// left empty.
+func (m *SnapshotResourceModel) snapshotSpec(ctx context.Context) (spec service.SnapshotSpec, diags diag.Diagnostics) {
+	spec = service.SnapshotSpec{
+		Name: m.Name.ValueString(),
+		ImageSpec: service.ImageSpec{
+			ImageName:    m.ImageName.ValueString(),
+			ImageArchive: m.ImageArchive.ValueString(),
+			KeepLocalTag: m.KeepLocalTag.ValueBool(),
+			Build:        m.buildSpec(),
+			Platform:     m.Platform.ValueString(),
+			RemoteTag:    m.RemoteTag.ValueString(),
+			PushMode:     m.PushMode.ValueString(),
+		},
+		BuildContextHash: m.BuildContextHash.ValueString(),
+		RemoteImageName:  m.RemoteImageName.ValueString(),
+		Cpu:              m.Cpu.ValueInt32Pointer(),
+		Gpu:              m.Gpu.ValueInt32Pointer(),
+		Memory:           m.Memory.ValueInt32Pointer(),
+		Disk:             m.Disk.ValueInt32Pointer(),
+		VerifyOnCreate:   m.VerifyOnCreate.ValueBool(),
+		VerifyCommand:    m.VerifyCommand.ValueString(),
+		Async:            !m.WaitForActive.IsNull() && !m.WaitForActive.ValueBool(),
 	}
 
-	if snapshot.Size.IsSet() {
-		data.Size = types.Float32PointerValue(snapshot.Size.Get())
+	if !m.ImageSources.IsNull() && !m.ImageSources.IsUnknown() {
+		diags.Append(m.ImageSources.ElementsAs(ctx, &spec.ImageSources, false)...)
+	}
+	if !m.Entrypoint.IsNull() && !m.Entrypoint.IsUnknown() {
+		diags.Append(m.Entrypoint.ElementsAs(ctx, &spec.Entrypoint, false)...)
 	}
 
 	return
 }
 
-func (r *SnapshotResource) deleteSnapshot(ctx context.Context, data *SnapshotResourceModel) (infos, warns, errors diag.Diagnostics) {
-	resp, err := r.client.SnapshotsAPI.RemoveSnapshot(ctx, data.Id.ValueString()).Execute()
-	if resp != nil && resp.Body != nil {
-		defer resp.Body.Close()
+// buildSpec returns how to build the image, or nil if it isn't built.
+func (m *SnapshotResourceModel) buildSpec() *service.BuildSpec {
+	if m.BuildContext.IsNull() {
+		return nil
 	}
-	if err != nil && resp != nil && resp.StatusCode == 404 {
-		return
-	} else if err != nil {
-		errors.AddError("Client Error", fmt.Sprintf("Unable to delete snapshot, got error: %v", err))
-		return
+
+	return &service.BuildSpec{
+		Context:    m.BuildContext.ValueString(),
+		Dockerfile: m.Dockerfile.ValueString(),
+		Target:     m.BuildTarget.ValueString(),
 	}
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			errors.AddError("Deletion Error", fmt.Sprintf("Cancelled while waiting for snapshot deletion: %v", ctx.Err()))
-			return
-		default:
-			_, resp, err := r.client.SnapshotsAPI.GetSnapshot(ctx, data.Id.ValueString()).Execute()
-			if resp != nil && resp.Body != nil {
-				defer resp.Body.Close()
-			}
-			if err != nil && resp != nil && resp.StatusCode == 404 {
-				tflog.Info(ctx, "Snapshot successfully deleted")
-				return
-			} else if err != nil {
-				errors.AddError("Deletion Verification Error", fmt.Sprintf("Unable to verify snapshot deletion: %v", err))
-				return
-			}
-
-			tflog.Info(ctx, "Waiting for snapshot to be deleted")
-			time.Sleep(time.Second)
-		}
+// setLocalImageID records the local image that was pushed. Snapshots that
+// didn't push one keep the planned ID.
+func (m *SnapshotResourceModel) setLocalImageID(pushed service.PushedImage) {
+	if pushed.LocalImageID != "" {
+		m.LocalImageID = types.StringValue(pushed.LocalImageID)
+	} else if m.LocalImageID.IsUnknown() {
+		m.LocalImageID = types.StringNull()
 	}
 }
+
+// setSnapshot fills in the attributes reported by the API.
+func (m *SnapshotResourceModel) setSnapshot(snapshot *apiclient.SnapshotDto) {
+	m.Id = types.StringValue(snapshot.Id)
+	m.Name = types.StringValue(snapshot.Name)
+	m.Cpu = types.Int32Value(int32(snapshot.Cpu))
+	m.Gpu = types.Int32Value(int32(snapshot.Gpu))
+	m.Memory = types.Int32Value(int32(snapshot.Mem))
+	m.Disk = types.Int32Value(int32(snapshot.Disk))
+	m.CreatedAt = types.StringValue(snapshot.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
+	m.OrganizationId = types.StringPointerValue(snapshot.OrganizationId)
+	m.RemoteImageName = types.StringPointerValue(snapshot.ImageName)
+	m.Size = types.Float32PointerValue(snapshot.Size.Get())
+	m.State = types.StringValue(string(snapshot.State))
+}
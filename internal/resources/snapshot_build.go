@@ -0,0 +1,313 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+	"github.com/geldata/terraform-provider-daytona/internal/service"
+)
+
+var _ resource.Resource = &SnapshotBuildResource{}
+var _ resource.ResourceWithValidateConfig = &SnapshotBuildResource{}
+var _ resource.ResourceWithModifyPlan = &SnapshotBuildResource{}
+
+func NewSnapshotBuildResource() resource.Resource {
+	return &SnapshotBuildResource{}
+}
+
+type SnapshotBuildResource struct {
+	service *service.Service
+}
+
+type SnapshotBuildResourceModel struct {
+	Id                types.String  `tfsdk:"id"`
+	Name              types.String  `tfsdk:"name"`
+	DockerfileContent types.String  `tfsdk:"dockerfile_content"`
+	ContextHashes     types.List    `tfsdk:"context_hashes"`
+	OrganizationId    types.String  `tfsdk:"organization_id"`
+	Size              types.Float32 `tfsdk:"size"`
+	Cpu               types.Int32   `tfsdk:"cpu"`
+	Gpu               types.Int32   `tfsdk:"gpu"`
+	Memory            types.Int32   `tfsdk:"memory"`
+	Disk              types.Int32   `tfsdk:"disk"`
+	CreatedAt         types.String  `tfsdk:"created_at"`
+	KeepRemotely      types.Bool    `tfsdk:"keep_remotely"`
+	DestroyBehavior   types.String  `tfsdk:"destroy_behavior"`
+	VerifyOnCreate    types.Bool    `tfsdk:"verify_on_create"`
+	VerifyCommand     types.String  `tfsdk:"verify_command"`
+}
+
+func (r *SnapshotBuildResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snapshot_build"
+}
+
+func (r *SnapshotBuildResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a Daytona snapshot built server-side from a Dockerfile, without a local Docker daemon. The Dockerfile can only `COPY` or `ADD` files from build contexts already uploaded to Daytona's object storage, referenced by `context_hashes`",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the snapshot",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the snapshot",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"dockerfile_content": schema.StringAttribute{
+				MarkdownDescription: "The contents of the Dockerfile to build the snapshot from",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"context_hashes": schema.ListAttribute{
+				MarkdownDescription: "Hashes of build contexts in Daytona's object storage, as uploaded by the Daytona SDKs, that the Dockerfile can `COPY` or `ADD` files from",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The organization ID for the snapshot",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"size": schema.Float32Attribute{
+				MarkdownDescription: "The size of the snapshot in bytes",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Float32{
+					float32planmodifier.UseStateForUnknown(),
+				},
+			},
+			"cpu": schema.Int32Attribute{
+				MarkdownDescription: "CPU cores allocated to the resulting sandbox",
+				Optional:            true,
+				Computed:            true,
+				Default:             int32default.StaticInt32(1),
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.RequiresReplace(),
+				},
+			},
+			"gpu": schema.Int32Attribute{
+				MarkdownDescription: "GPU units allocated to the resulting sandbox",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.UseStateForUnknown(),
+				},
+			},
+			"memory": schema.Int32Attribute{
+				MarkdownDescription: "Memory allocated to the resulting sandbox in GB",
+				Optional:            true,
+				Computed:            true,
+				Default:             int32default.StaticInt32(1),
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.RequiresReplace(),
+				},
+			},
+			"disk": schema.Int32Attribute{
+				MarkdownDescription: "Disk space allocated to the resulting sandbox in GB",
+				Optional:            true,
+				Computed:            true,
+				Default:             int32default.StaticInt32(3),
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.RequiresReplace(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The creation timestamp of the snapshot",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"keep_remotely":    keepRemotelyAttribute(),
+			"destroy_behavior": destroyBehaviorAttribute(),
+			"verify_on_create": schema.BoolAttribute{
+				MarkdownDescription: "Whether to start a temporary sandbox from the snapshot after it becomes active, failing the creation if the sandbox can't start",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"verify_command": schema.StringAttribute{
+				MarkdownDescription: "Health-check command to run inside the temporary verification sandbox. A non-zero exit code fails the creation. Only used when `verify_on_create` is enabled",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *SnapshotBuildResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data SnapshotBuildResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateDestroyBehavior(data.KeepRemotely, data.DestroyBehavior)...)
+}
+
+// ModifyPlan fails plans replacing a snapshot that destroy_behavior keeps
+// with one of the same name, which creating the replacement would delete.
+func (r *SnapshotBuildResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var data, state SnapshotBuildResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || !data.Name.Equal(state.Name) {
+		return
+	}
+
+	behavior := destroyBehavior(state.KeepRemotely, state.DestroyBehavior)
+	if behavior == service.DestroyBehaviorDelete {
+		return
+	}
+
+	// every change of these replaces the snapshot, unknown values included
+	if !data.DockerfileContent.Equal(state.DockerfileContent) || !data.ContextHashes.Equal(state.ContextHashes) ||
+		!data.Cpu.Equal(state.Cpu) || !data.Gpu.Equal(state.Gpu) || !data.Memory.Equal(state.Memory) || !data.Disk.Equal(state.Disk) {
+		resp.Diagnostics.Append(service.ReplacementNameConflict(data.Name.ValueString(), behavior))
+	}
+}
+
+func (r *SnapshotBuildResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.service = service.New(client)
+}
+
+func (r *SnapshotBuildResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SnapshotBuildResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var contextHashes []string
+	resp.Diagnostics.Append(data.ContextHashes.ElementsAs(ctx, &contextHashes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	snapshot, _, warns, errors := r.service.CreateSnapshot(ctx, service.SnapshotSpec{
+		Name:              data.Name.ValueString(),
+		DockerfileContent: data.DockerfileContent.ValueString(),
+		ContextHashes:     contextHashes,
+		Cpu:               data.Cpu.ValueInt32Pointer(),
+		Memory:            data.Memory.ValueInt32Pointer(),
+		Disk:              data.Disk.ValueInt32Pointer(),
+		VerifyOnCreate:    data.VerifyOnCreate.ValueBool(),
+		VerifyCommand:     data.VerifyCommand.ValueString(),
+	})
+	resp.Diagnostics.Append(warns...)
+	resp.Diagnostics.Append(errors...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.setSnapshot(snapshot)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SnapshotBuildResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SnapshotBuildResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	snapshot, errors := r.service.GetSnapshot(ctx, data.Id.ValueString())
+	resp.Diagnostics.Append(errors...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if snapshot == nil {
+		tflog.Info(ctx, "Snapshot no longer exists, removing it from state", map[string]any{
+			"snapshot_id": data.Id.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.setSnapshot(snapshot)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SnapshotBuildResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SnapshotBuildResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// everything that changes the snapshot requires a replacement, only
+	// settings used on create and delete can change in place
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SnapshotBuildResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *SnapshotBuildResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.service.DestroySnapshot(ctx, data.Id.ValueString(), data.Name.ValueString(), destroyBehavior(data.KeepRemotely, data.DestroyBehavior))...)
+}
+
+// setSnapshot fills in the attributes reported by the API.
+func (m *SnapshotBuildResourceModel) setSnapshot(snapshot *apiclient.SnapshotDto) {
+	m.Id = types.StringValue(snapshot.Id)
+	m.Name = types.StringValue(snapshot.Name)
+	m.Cpu = types.Int32Value(int32(snapshot.Cpu))
+	m.Gpu = types.Int32Value(int32(snapshot.Gpu))
+	m.Memory = types.Int32Value(int32(snapshot.Mem))
+	m.Disk = types.Int32Value(int32(snapshot.Disk))
+	m.CreatedAt = types.StringValue(snapshot.CreatedAt.Format("2006-01-02T15:04:05Z07:00"))
+	m.OrganizationId = types.StringPointerValue(snapshot.OrganizationId)
+	m.Size = types.Float32PointerValue(snapshot.Size.Get())
+}
@@ -0,0 +1,56 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/geldata/terraform-provider-daytona/internal/service"
+)
+
+func TestSnapshotBuildResourceModifyPlan(t *testing.T) {
+	schemaResp := &resource.SchemaResponse{}
+	(&SnapshotBuildResource{}).Schema(context.Background(), resource.SchemaRequest{}, schemaResp)
+
+	stateModel := SnapshotBuildResourceModel{
+		Id:                types.StringValue("snapshot-app"),
+		Name:              types.StringValue("app"),
+		DockerfileContent: types.StringValue("FROM ubuntu"),
+		ContextHashes:     types.ListNull(types.StringType),
+		Cpu:               types.Int32Value(1),
+	}
+
+	tests := map[string]struct {
+		destroyBehavior string
+		name            string
+		dockerfile      string
+		expected        []string
+	}{
+		"retained under the same name": {destroyBehavior: service.DestroyBehaviorRetain, name: "app", dockerfile: "FROM debian", expected: []string{"Snapshot Name Conflict"}},
+		"retained under another name":  {destroyBehavior: service.DestroyBehaviorRetain, name: "app-v2", dockerfile: "FROM debian"},
+		"deleted":                      {destroyBehavior: service.DestroyBehaviorDelete, name: "app", dockerfile: "FROM debian"},
+		"retained without replacement": {destroyBehavior: service.DestroyBehaviorRetain, name: "app", dockerfile: "FROM ubuntu"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prior := stateModel
+			prior.DestroyBehavior = types.StringValue(test.destroyBehavior)
+			planned := prior
+			planned.Name = types.StringValue(test.name)
+			planned.DockerfileContent = types.StringValue(test.dockerfile)
+
+			state := tfsdk.State{Schema: schemaResp.Schema}
+			requireNoErrors(t, state.Set(context.Background(), &prior))
+			plan := tfsdk.Plan{Schema: schemaResp.Schema}
+			requireNoErrors(t, plan.Set(context.Background(), &planned))
+
+			resp := &resource.ModifyPlanResponse{Plan: plan}
+			(&SnapshotBuildResource{}).ModifyPlan(context.Background(), resource.ModifyPlanRequest{Plan: plan, State: state}, resp)
+			requireErrors(t, resp.Diagnostics, test.expected...)
+		})
+	}
+}
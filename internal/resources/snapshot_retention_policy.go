@@ -0,0 +1,321 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+	"github.com/geldata/terraform-provider-daytona/internal/service"
+)
+
+var _ resource.Resource = &SnapshotRetentionPolicyResource{}
+var _ resource.ResourceWithModifyPlan = &SnapshotRetentionPolicyResource{}
+var _ resource.ResourceWithValidateConfig = &SnapshotRetentionPolicyResource{}
+
+func NewSnapshotRetentionPolicyResource() resource.Resource {
+	return &SnapshotRetentionPolicyResource{}
+}
+
+type SnapshotRetentionPolicyResource struct {
+	service *service.Service
+}
+
+type SnapshotRetentionPolicyResourceModel struct {
+	Id               types.String `tfsdk:"id"`
+	NamePrefix       types.String `tfsdk:"name_prefix"`
+	MaxAge           types.String `tfsdk:"max_age"`
+	MaxCount         types.Int32  `tfsdk:"max_count"`
+	KeepNames        types.Set    `tfsdk:"keep_names"`
+	KeepUsedWithin   types.String `tfsdk:"keep_used_within"`
+	ExpiredSnapshots types.Set    `tfsdk:"expired_snapshots"`
+}
+
+func (r *SnapshotRetentionPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snapshot_retention_policy"
+}
+
+func (r *SnapshotRetentionPolicyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deletes stale snapshots whose name starts with a prefix. Daytona has no server-side retention, so the policy is enforced by the provider: planning lists the snapshots that expired, and applying deletes them. General snapshots and snapshots still being processed are never deleted",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The name prefix of the policy",
+				Computed:            true,
+			},
+			"name_prefix": schema.StringAttribute{
+				MarkdownDescription: "Only snapshots whose name starts with this prefix are subject to the policy",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"max_age": schema.StringAttribute{
+				MarkdownDescription: "Snapshots created longer ago than this duration expire, e.g. `720h`",
+				Optional:            true,
+			},
+			"max_count": schema.Int32Attribute{
+				MarkdownDescription: "Only the newest `max_count` matching snapshots are kept, older ones expire",
+				Optional:            true,
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
+			},
+			"keep_names": schema.SetAttribute{
+				MarkdownDescription: "Names of snapshots that never expire, e.g. the ones referenced by `daytona_snapshot` resources",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"keep_used_within": schema.StringAttribute{
+				MarkdownDescription: "Snapshots a sandbox was started from within this duration never expire, e.g. `168h`",
+				Optional:            true,
+			},
+			"expired_snapshots": schema.SetAttribute{
+				MarkdownDescription: "Names of the expired snapshots, listed when planning and deleted by the apply. Snapshots that expire after planning are left for the next plan, and planned ones that no longer expire, e.g. as a sandbox was started from them, aren't deleted",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *SnapshotRetentionPolicyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.service = service.New(client)
+}
+
+func (r *SnapshotRetentionPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data SnapshotRetentionPolicyResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags := parseDuration(path.Root("max_age"), data.MaxAge)
+	resp.Diagnostics.Append(diags...)
+	_, diags = parseDuration(path.Root("keep_used_within"), data.KeepUsedWithin)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ModifyPlan lists the snapshots that expired, so the plan shows the ones the
+// apply deletes. They're only known after apply while the policy or the
+// provider configuration isn't known yet.
+func (r *SnapshotRetentionPolicyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data *SnapshotRetentionPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = data.NamePrefix
+	data.ExpiredSnapshots = types.SetUnknown(types.StringType)
+
+	if r.service != nil && data.policyKnown() {
+		policy, diags := data.policy(ctx)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		expired, errors := r.service.ExpiredSnapshots(ctx, policy)
+		resp.Diagnostics.Append(errors...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		data.ExpiredSnapshots, diags = snapshotNames(ctx, expired)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &data)...)
+}
+
+func (r *SnapshotRetentionPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *SnapshotRetentionPolicyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.enforce(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SnapshotRetentionPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *SnapshotRetentionPolicyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the snapshots deleted by the last apply are gone, and the next plan lists
+	// the ones that expired since
+	data.ExpiredSnapshots = types.SetValueMust(types.StringType, nil)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SnapshotRetentionPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *SnapshotRetentionPolicyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.enforce(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SnapshotRetentionPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// the policy only exists in Terraform, there is nothing to remove
+}
+
+// enforce deletes the planned expired snapshots that still expire under the
+// policy, or all the expired ones if they weren't known when planning.
+func (r *SnapshotRetentionPolicyResource) enforce(ctx context.Context, data *SnapshotRetentionPolicyResourceModel) (diags diag.Diagnostics) {
+	policy, diags := data.policy(ctx)
+	if diags.HasError() {
+		return
+	}
+
+	planned := !data.ExpiredSnapshots.IsUnknown()
+	var plannedNames []string
+	if planned {
+		diags.Append(data.ExpiredSnapshots.ElementsAs(ctx, &plannedNames, false)...)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	expired, errors := r.service.ExpiredSnapshots(ctx, policy)
+	diags.Append(errors...)
+	if diags.HasError() {
+		return
+	}
+
+	var deleted []apiclient.SnapshotDto
+	for _, snapshot := range expired {
+		if planned && !slices.Contains(plannedNames, snapshot.Name) {
+			tflog.Info(ctx, "Leaving snapshot that expired after planning", map[string]any{
+				"snapshot_name": snapshot.Name,
+			})
+			continue
+		}
+
+		tflog.Info(ctx, "Deleting expired snapshot", map[string]any{
+			"snapshot_id":   snapshot.Id,
+			"snapshot_name": snapshot.Name,
+			"created_at":    snapshot.CreatedAt.Format(time.RFC3339),
+		})
+
+		diags.Append(r.service.DeleteSnapshot(ctx, snapshot.Id)...)
+		if diags.HasError() {
+			return
+		}
+		deleted = append(deleted, snapshot)
+	}
+
+	data.Id = data.NamePrefix
+	if !planned {
+		data.ExpiredSnapshots, errors = snapshotNames(ctx, deleted)
+		diags.Append(errors...)
+	}
+	return
+}
+
+// snapshotNames returns the set of the snapshots' names.
+func snapshotNames(ctx context.Context, snapshots []apiclient.SnapshotDto) (types.Set, diag.Diagnostics) {
+	names := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		names = append(names, snapshot.Name)
+	}
+	return types.SetValueFrom(ctx, types.StringType, names)
+}
+
+// policyKnown reports whether all the attributes of the policy are known, so
+// the snapshots it expires can be listed.
+func (m *SnapshotRetentionPolicyResourceModel) policyKnown() bool {
+	if m.NamePrefix.IsUnknown() || m.MaxAge.IsUnknown() || m.MaxCount.IsUnknown() || m.KeepUsedWithin.IsUnknown() || m.KeepNames.IsUnknown() {
+		return false
+	}
+	for _, name := range m.KeepNames.Elements() {
+		if name.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+// policy converts the model into the service's retention policy.
+func (m *SnapshotRetentionPolicyResourceModel) policy(ctx context.Context) (policy service.RetentionPolicy, diags diag.Diagnostics) {
+	policy = service.RetentionPolicy{
+		NamePrefix: m.NamePrefix.ValueString(),
+		MaxCount:   m.MaxCount.ValueInt32Pointer(),
+	}
+
+	policy.MaxAge, diags = parseDuration(path.Root("max_age"), m.MaxAge)
+	keepUsedWithin, errors := parseDuration(path.Root("keep_used_within"), m.KeepUsedWithin)
+	diags.Append(errors...)
+	policy.KeepUsedWithin = keepUsedWithin
+
+	if !m.KeepNames.IsNull() {
+		diags.Append(m.KeepNames.ElementsAs(ctx, &policy.KeepNames, false)...)
+	}
+
+	return
+}
+
+// parseDuration parses an optional, non-negative Go duration string, zero if
+// it's unset.
+func parseDuration(attribute path.Path, value types.String) (duration time.Duration, diags diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() {
+		return
+	}
+
+	duration, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(attribute, "Invalid Attribute Value", fmt.Sprintf("Unable to parse duration %q: %v", value.ValueString(), err))
+	} else if duration < 0 {
+		diags.AddAttributeError(attribute, "Invalid Attribute Value", fmt.Sprintf("Duration %q is negative", value.ValueString()))
+	}
+	return
+}
@@ -0,0 +1,145 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// newRetentionTestAPI returns a Daytona API with active snapshots app-1 to
+// app-3, app-1 being the oldest.
+func newRetentionTestAPI() *fakeSnapshotAPI {
+	api := newFakeSnapshotAPI("")
+	for i, name := range []string{"app-1", "app-2", "app-3"} {
+		api.snapshots["snapshot-"+name] = &apiclient.SnapshotDto{
+			Id:        "snapshot-" + name,
+			Name:      name,
+			State:     apiclient.SNAPSHOTSTATE_ACTIVE,
+			CreatedAt: time.Now().Add(time.Duration(i-3) * time.Hour),
+		}
+	}
+	return api
+}
+
+func newRetentionPolicyModel(maxCount int32) SnapshotRetentionPolicyResourceModel {
+	return SnapshotRetentionPolicyResourceModel{
+		Id:               types.StringUnknown(),
+		NamePrefix:       types.StringValue("app-"),
+		MaxAge:           types.StringNull(),
+		MaxCount:         types.Int32Value(maxCount),
+		KeepNames:        types.SetNull(types.StringType),
+		KeepUsedWithin:   types.StringNull(),
+		ExpiredSnapshots: types.SetUnknown(types.StringType),
+	}
+}
+
+func retentionPolicyPlan(t *testing.T, model SnapshotRetentionPolicyResourceModel) tfsdk.Plan {
+	t.Helper()
+
+	resp := &resource.SchemaResponse{}
+	(&SnapshotRetentionPolicyResource{}).Schema(context.Background(), resource.SchemaRequest{}, resp)
+	requireNoErrors(t, resp.Diagnostics)
+
+	plan := tfsdk.Plan{Schema: resp.Schema}
+	requireNoErrors(t, plan.Set(context.Background(), &model))
+	return plan
+}
+
+func expiredSnapshotNames(t *testing.T, model SnapshotRetentionPolicyResourceModel) (names []string) {
+	t.Helper()
+
+	requireNoErrors(t, model.ExpiredSnapshots.ElementsAs(context.Background(), &names, false))
+	return
+}
+
+func TestSnapshotRetentionPolicyResourceValidateConfig(t *testing.T) {
+	tests := map[string]struct {
+		maxAge, keepUsedWithin string
+		errors                 []string
+	}{
+		"valid":    {maxAge: "720h", keepUsedWithin: "168h"},
+		"invalid":  {maxAge: "a month", keepUsedWithin: "168h", errors: []string{"Invalid Attribute Value"}},
+		"negative": {maxAge: "720h", keepUsedWithin: "-1h", errors: []string{"Invalid Attribute Value"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			model := newRetentionPolicyModel(1)
+			model.MaxAge = types.StringValue(test.maxAge)
+			model.KeepUsedWithin = types.StringValue(test.keepUsedWithin)
+
+			resp := &resource.ValidateConfigResponse{}
+			(&SnapshotRetentionPolicyResource{}).ValidateConfig(context.Background(), resource.ValidateConfigRequest{
+				Config: tfsdk.Config(retentionPolicyPlan(t, model)),
+			}, resp)
+			requireErrors(t, resp.Diagnostics, test.errors...)
+		})
+	}
+}
+
+func TestSnapshotRetentionPolicyResourceModifyPlan(t *testing.T) {
+	ctx := context.Background()
+	r := &SnapshotRetentionPolicyResource{service: newTestSnapshotResource(newRetentionTestAPI(), nil).service}
+
+	model := newRetentionPolicyModel(1)
+	resp := &resource.ModifyPlanResponse{Plan: retentionPolicyPlan(t, model)}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{Plan: resp.Plan}, resp)
+	requireNoErrors(t, resp.Diagnostics)
+
+	var planned SnapshotRetentionPolicyResourceModel
+	requireNoErrors(t, resp.Plan.Get(ctx, &planned))
+	if names := expiredSnapshotNames(t, planned); len(names) != 2 || names[0] == "app-3" || names[1] == "app-3" {
+		t.Errorf("expected app-1 and app-2 to be planned for deletion, got %v", names)
+	}
+	if planned.Id.ValueString() != "app-" {
+		t.Errorf("expected the planned ID to be the name prefix, got %s", planned.Id)
+	}
+
+	// with an unknown policy, the expired snapshots are only known after apply
+	model.MaxCount = types.Int32Unknown()
+	resp = &resource.ModifyPlanResponse{Plan: retentionPolicyPlan(t, model)}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{Plan: resp.Plan}, resp)
+	requireNoErrors(t, resp.Diagnostics)
+
+	var expired types.Set
+	requireNoErrors(t, resp.Plan.GetAttribute(ctx, path.Root("expired_snapshots"), &expired))
+	if !expired.IsUnknown() {
+		t.Errorf("expected the expired snapshots to be unknown, got %s", expired)
+	}
+}
+
+func TestSnapshotRetentionPolicyResourceDeletesPlannedSnapshots(t *testing.T) {
+	ctx := context.Background()
+	api := newRetentionTestAPI()
+	r := &SnapshotRetentionPolicyResource{service: newTestSnapshotResource(api, nil).service}
+
+	// app-1 was planned, app-2 only expired after planning
+	model := newRetentionPolicyModel(1)
+	model.ExpiredSnapshots = types.SetValueMust(types.StringType, []attr.Value{types.StringValue("app-1")})
+
+	plan := retentionPolicyPlan(t, model)
+	resp := &resource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil)}}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, resp)
+	requireNoErrors(t, resp.Diagnostics)
+
+	if _, ok := api.snapshots["snapshot-app-1"]; ok {
+		t.Error("expected the planned snapshot to be deleted")
+	}
+	if _, ok := api.snapshots["snapshot-app-2"]; !ok {
+		t.Error("expected the snapshot that expired after planning to be kept")
+	}
+
+	var state SnapshotRetentionPolicyResourceModel
+	requireNoErrors(t, resp.State.Get(ctx, &state))
+	if names := expiredSnapshotNames(t, state); len(names) != 1 || names[0] != "app-1" {
+		t.Errorf("expected the planned expired snapshots in the state, got %v", names)
+	}
+}
@@ -0,0 +1,590 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/geldata/terraform-provider-daytona/internal/service"
+)
+
+// fakeSnapshotAPI is an in-memory Daytona API for the snapshot lifecycle.
+// Registering snapshots fails while createErr is set, and the snapshots
+// registered are active right away.
+type fakeSnapshotAPI struct {
+	service.DaytonaAPI
+
+	registryURL string
+	snapshots   map[string]*apiclient.SnapshotDto
+	createErr   error
+	// organization is returned by GetOrganization, which fails without one
+	organization *apiclient.Organization
+	calls        []string
+}
+
+func newFakeSnapshotAPI(registryURL string) *fakeSnapshotAPI {
+	return &fakeSnapshotAPI{registryURL: registryURL, snapshots: map[string]*apiclient.SnapshotDto{}}
+}
+
+func (f *fakeSnapshotAPI) GetSnapshot(ctx context.Context, idOrName string) (*apiclient.SnapshotDto, error) {
+	for _, snapshot := range f.snapshots {
+		if snapshot.Id == idOrName || snapshot.Name == idOrName {
+			copied := *snapshot
+			return &copied, nil
+		}
+	}
+	return nil, service.ErrNotFound
+}
+
+func (f *fakeSnapshotAPI) ListSnapshots(ctx context.Context) (snapshots []apiclient.SnapshotDto, err error) {
+	for _, snapshot := range f.snapshots {
+		snapshots = append(snapshots, *snapshot)
+	}
+	return
+}
+
+func (f *fakeSnapshotAPI) CreateSnapshot(ctx context.Context, createRequest apiclient.CreateSnapshot) (*apiclient.SnapshotDto, error) {
+	f.calls = append(f.calls, "CreateSnapshot "+createRequest.Name)
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+
+	snapshot := apiclient.NewSnapshotDtoWithDefaults()
+	snapshot.Id = "snapshot-" + createRequest.Name
+	snapshot.Name = createRequest.Name
+	snapshot.ImageName = createRequest.ImageName
+	snapshot.State = apiclient.SNAPSHOTSTATE_ACTIVE
+	snapshot.CreatedAt = time.Now()
+	f.snapshots[snapshot.Id] = snapshot
+
+	copied := *snapshot
+	return &copied, nil
+}
+
+func (f *fakeSnapshotAPI) RemoveSnapshot(ctx context.Context, id string) error {
+	f.calls = append(f.calls, "RemoveSnapshot "+id)
+	if _, ok := f.snapshots[id]; !ok {
+		return service.ErrNotFound
+	}
+	delete(f.snapshots, id)
+	return nil
+}
+
+func (f *fakeSnapshotAPI) GetTransientPushAccess(ctx context.Context) (*apiclient.RegistryPushAccessDto, error) {
+	return &apiclient.RegistryPushAccessDto{
+		Username:    "user",
+		Secret:      "secret",
+		RegistryUrl: f.registryURL,
+		Project:     "project",
+	}, nil
+}
+
+func (f *fakeSnapshotAPI) GetOrganization(ctx context.Context) (*apiclient.Organization, error) {
+	if f.organization == nil {
+		return nil, errors.New("forbidden")
+	}
+	return f.organization, nil
+}
+
+// fakeLocalImages is a Docker daemon that only knows the IDs of its images.
+type fakeLocalImages struct {
+	service.DockerAPI
+
+	ids map[string]string
+}
+
+func (d *fakeLocalImages) ImageInspectWithRaw(ctx context.Context, image string) (dockertypes.ImageInspect, []byte, error) {
+	id, ok := d.ids[image]
+	if !ok {
+		return dockertypes.ImageInspect{}, nil, errors.New("no such image")
+	}
+	return dockertypes.ImageInspect{ID: id}, nil, nil
+}
+
+func (d *fakeLocalImages) Close() error {
+	return nil
+}
+
+// newTestRegistry starts an in-memory registry, which only serves pulls once
+// pullOnly is set. Pushes fail even for images it has, as they start with
+// HEAD requests.
+func newTestRegistry(t *testing.T) (server *httptest.Server, pullOnly *atomic.Bool) {
+	t.Helper()
+
+	pullOnly = &atomic.Bool{}
+	handler := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if pullOnly.Load() && req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	}))
+	t.Cleanup(server.Close)
+	return server, pullOnly
+}
+
+func newTestSnapshotResource(api service.DaytonaAPI, httpClient *http.Client) *SnapshotResource {
+	return &SnapshotResource{service: &service.Service{
+		API: api,
+		NewDocker: func() (service.DockerAPI, error) {
+			return nil, errors.New("no Docker daemon")
+		},
+		PollInterval:    time.Millisecond,
+		MaxPollInterval: time.Millisecond,
+		HTTPClient:      httpClient,
+	}}
+}
+
+func snapshotSchema(t *testing.T) schema.Schema {
+	t.Helper()
+
+	resp := &resource.SchemaResponse{}
+	(&SnapshotResource{}).Schema(context.Background(), resource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics.Errors())
+	}
+	return resp.Schema
+}
+
+// newSnapshotModel returns a model of the snapshot with all other attributes
+// null.
+func newSnapshotModel(name string) SnapshotResourceModel {
+	return SnapshotResourceModel{
+		Name:         types.StringValue(name),
+		ImageSources: types.ListNull(types.StringType),
+		Entrypoint:   types.ListNull(types.StringType),
+	}
+}
+
+func snapshotPlan(t *testing.T, model SnapshotResourceModel) tfsdk.Plan {
+	t.Helper()
+
+	plan := tfsdk.Plan{Schema: snapshotSchema(t)}
+	if diags := plan.Set(context.Background(), &model); diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags.Errors())
+	}
+	return plan
+}
+
+func snapshotState(t *testing.T, model SnapshotResourceModel) tfsdk.State {
+	t.Helper()
+
+	state := tfsdk.State{Schema: snapshotSchema(t)}
+	if diags := state.Set(context.Background(), &model); diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags.Errors())
+	}
+	return state
+}
+
+func nullSnapshotState(t *testing.T) tfsdk.State {
+	t.Helper()
+
+	schema := snapshotSchema(t)
+	return tfsdk.State{Schema: schema, Raw: tftypes.NewValue(schema.Type().TerraformType(context.Background()), nil)}
+}
+
+func requireNoErrors(t *testing.T, diags diag.Diagnostics) {
+	t.Helper()
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags.Errors())
+	}
+}
+
+// requireErrors checks the summaries of the errors, in any order.
+func requireErrors(t *testing.T, diags diag.Diagnostics, summaries ...string) {
+	t.Helper()
+
+	var got []string
+	for _, d := range diags.Errors() {
+		got = append(got, d.Summary())
+	}
+	slices.Sort(got)
+	summaries = slices.Clone(summaries)
+	slices.Sort(summaries)
+	if !slices.Equal(got, summaries) {
+		t.Errorf("expected errors %q, got %q", summaries, got)
+	}
+}
+
+func modifySnapshotPlan(t *testing.T, r *SnapshotResource, plan tfsdk.Plan, state tfsdk.State) *resource.ModifyPlanResponse {
+	t.Helper()
+
+	resp := &resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(context.Background(), resource.ModifyPlanRequest{Plan: plan, State: state}, resp)
+	return resp
+}
+
+func createSnapshot(t *testing.T, r *SnapshotResource, plan tfsdk.Plan) *resource.CreateResponse {
+	t.Helper()
+
+	resp := &resource.CreateResponse{State: tfsdk.State{
+		Schema: plan.Schema,
+		Raw:    tftypes.NewValue(plan.Schema.Type().TerraformType(context.Background()), nil),
+	}}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan}, resp)
+	return resp
+}
+
+func TestSnapshotResourceCreateRetriesWithoutPushingAgain(t *testing.T) {
+	server, pullOnly := newTestRegistry(t)
+	registryURL := strings.TrimPrefix(server.URL, "http://")
+
+	image, err := random.Image(256, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "app.tar")
+	if err := tarball.WriteToFile(archivePath, name.MustParseReference("app:1.0"), image); err != nil {
+		t.Fatal(err)
+	}
+
+	api := newFakeSnapshotAPI(registryURL)
+	api.createErr = errors.New("registration failed")
+	r := newTestSnapshotResource(api, server.Client())
+
+	model := newSnapshotModel("app")
+	model.ImageName = types.StringValue("app:1.0")
+	model.ImageSources = types.ListValueMust(types.StringType, []attr.Value{types.StringValue(service.ImageSourceArchive)})
+	model.ImageArchive = types.StringValue(archivePath)
+	model.RemoteTag = types.StringValue("{tag}-{digest}")
+	model.PushMode = types.StringValue(service.PushModeDirect)
+	plan := snapshotPlan(t, model)
+
+	resp := createSnapshot(t, r, plan)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected the registration to fail")
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("expected no state for the failed creation")
+	}
+
+	// the image is in the registry now, pushing it again would fail
+	pullOnly.Store(true)
+	api.createErr = nil
+
+	resp = createSnapshot(t, r, plan)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics.Errors())
+	}
+
+	var state SnapshotResourceModel
+	if diags := resp.State.Get(context.Background(), &state); diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags.Errors())
+	}
+	if state.Id.ValueString() != "snapshot-app" {
+		t.Errorf("expected the registered snapshot's ID, got %q", state.Id.ValueString())
+	}
+	if remoteImageName := state.RemoteImageName.ValueString(); !strings.HasPrefix(remoteImageName, registryURL+"/project/app:1.0-") {
+		t.Errorf("expected the snapshot to be registered from the pushed image, got %q", remoteImageName)
+	}
+}
+
+func TestSnapshotResourceDeleteWithoutID(t *testing.T) {
+	api := newFakeSnapshotAPI("registry.example.com")
+	r := newTestSnapshotResource(api, nil)
+
+	state := snapshotState(t, newSnapshotModel("app"))
+	resp := &resource.DeleteResponse{State: state}
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics.Errors())
+	}
+	if len(api.calls) != 0 {
+		t.Errorf("expected no API calls without a snapshot ID, got %v", api.calls)
+	}
+}
+
+func TestSnapshotResourceValidateConfig(t *testing.T) {
+	withImage := func(modify func(*SnapshotResourceModel)) SnapshotResourceModel {
+		model := newSnapshotModel("app")
+		model.ImageName = types.StringValue("app:1.0")
+		modify(&model)
+		return model
+	}
+
+	tests := map[string]struct {
+		model    SnapshotResourceModel
+		expected []string
+	}{
+		"image": {
+			model: withImage(func(m *SnapshotResourceModel) {}),
+		},
+		"gpu with default memory": {
+			model:    withImage(func(m *SnapshotResourceModel) { m.Gpu = types.Int32Value(1) }),
+			expected: []string{"Insufficient Memory For GPU"},
+		},
+		"gpu with enough memory": {
+			model: withImage(func(m *SnapshotResourceModel) {
+				m.Gpu = types.Int32Value(1)
+				m.Memory = types.Int32Value(minGpuSnapshotMemory)
+			}),
+		},
+		"gpu with unknown memory": {
+			model: withImage(func(m *SnapshotResourceModel) {
+				m.Gpu = types.Int32Value(1)
+				m.Memory = types.Int32Unknown()
+			}),
+		},
+		"build context without image name": {
+			model: func() SnapshotResourceModel {
+				model := newSnapshotModel("app")
+				model.RemoteImageName = types.StringValue("registry.example.com/project/app:1.0")
+				model.BuildContext = types.StringValue(".")
+				return model
+			}(),
+			expected: []string{"Missing Image Name"},
+		},
+		"dockerfile and target without build context": {
+			model: withImage(func(m *SnapshotResourceModel) {
+				m.Dockerfile = types.StringValue("Dockerfile.app")
+				m.BuildTarget = types.StringValue("release")
+			}),
+			expected: []string{"Missing Build Context", "Missing Build Context"},
+		},
+		"verifying without waiting": {
+			model: withImage(func(m *SnapshotResourceModel) {
+				m.VerifyOnCreate = types.BoolValue(true)
+				m.WaitForActive = types.BoolValue(false)
+			}),
+			expected: []string{"Conflicting Wait Configuration"},
+		},
+		"keep_remotely with delete": {
+			model: withImage(func(m *SnapshotResourceModel) {
+				m.KeepRemotely = types.BoolValue(true)
+				m.DestroyBehavior = types.StringValue("delete")
+			}),
+			expected: []string{"Conflicting Destroy Behavior"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := snapshotState(t, test.model)
+			resp := &resource.ValidateConfigResponse{}
+			(&SnapshotResource{}).ValidateConfig(context.Background(), resource.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw},
+			}, resp)
+
+			requireErrors(t, resp.Diagnostics, test.expected...)
+		})
+	}
+}
+
+func TestSnapshotResourcePlanBuildContextHash(t *testing.T) {
+	buildContext := t.TempDir()
+	os.WriteFile(filepath.Join(buildContext, "Dockerfile"), []byte("FROM ubuntu"), 0o644)
+	hash, err := service.BuildContextHash(service.BuildSpec{Context: buildContext})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model := newSnapshotModel("app")
+	model.ImageName = types.StringValue("app:1.0")
+	model.BuildContext = types.StringValue(buildContext)
+	model.BuildContextHash = types.StringUnknown()
+	plan := snapshotPlan(t, model)
+
+	built := model
+	built.Id = types.StringValue("snapshot-app")
+	built.BuildContextHash = types.StringValue(hash)
+	imported := built
+	imported.ImageName = types.StringNull()
+	imported.BuildContext = types.StringNull()
+	imported.BuildContextHash = types.StringNull()
+	changed := built
+	changed.BuildContextHash = types.StringValue("outdated")
+
+	tests := map[string]struct {
+		state           tfsdk.State
+		requiresReplace bool
+	}{
+		"create":    {state: nullSnapshotState(t)},
+		"unchanged": {state: snapshotState(t, built)},
+		"changed":   {state: snapshotState(t, changed), requiresReplace: true},
+		"imported":  {state: snapshotState(t, imported)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := modifySnapshotPlan(t, &SnapshotResource{}, plan, test.state)
+			requireNoErrors(t, resp.Diagnostics)
+
+			var planned types.String
+			requireNoErrors(t, resp.Plan.GetAttribute(context.Background(), path.Root("build_context_hash"), &planned))
+			if planned.ValueString() != hash {
+				t.Errorf("expected the hash %q to be planned, got %s", hash, planned)
+			}
+			if replaced := slices.ContainsFunc(resp.RequiresReplace, path.Root("build_context_hash").Equal); replaced != test.requiresReplace {
+				t.Errorf("expected replacement %t, got %t", test.requiresReplace, replaced)
+			}
+		})
+	}
+}
+
+func TestSnapshotResourcePlanBuildContextHashMissingContext(t *testing.T) {
+	model := newSnapshotModel("app")
+	model.ImageName = types.StringValue("app:1.0")
+	model.BuildContext = types.StringValue(filepath.Join(t.TempDir(), "missing"))
+	model.BuildContextHash = types.StringUnknown()
+
+	resp := modifySnapshotPlan(t, &SnapshotResource{}, snapshotPlan(t, model), nullSnapshotState(t))
+	requireErrors(t, resp.Diagnostics, "Build Context Error")
+}
+
+func TestSnapshotResourcePlanLocalImageID(t *testing.T) {
+	model := newSnapshotModel("app")
+	model.ImageName = types.StringValue("app:1.0")
+	model.LocalImageID = types.StringValue("sha256:pushed")
+	plan := snapshotPlan(t, model)
+
+	state := model
+	state.Id = types.StringValue("snapshot-app")
+
+	tests := map[string]struct {
+		localImages     map[string]string
+		requiresReplace bool
+	}{
+		"unchanged": {localImages: map[string]string{"app:1.0": "sha256:pushed"}},
+		"rebuilt":   {localImages: map[string]string{"app:1.0": "sha256:rebuilt"}, requiresReplace: true},
+		"missing":   {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := newTestSnapshotResource(newFakeSnapshotAPI("registry.example.com"), nil)
+			r.service.NewDocker = func() (service.DockerAPI, error) {
+				return &fakeLocalImages{ids: test.localImages}, nil
+			}
+
+			resp := modifySnapshotPlan(t, r, plan, snapshotState(t, state))
+			requireNoErrors(t, resp.Diagnostics)
+
+			var planned types.String
+			requireNoErrors(t, resp.Plan.GetAttribute(context.Background(), path.Root("local_image_id"), &planned))
+			if replaced := slices.ContainsFunc(resp.RequiresReplace, path.Root("local_image_id").Equal); replaced != test.requiresReplace {
+				t.Errorf("expected replacement %t, got %t", test.requiresReplace, replaced)
+			}
+			if planned.IsUnknown() != test.requiresReplace {
+				t.Errorf("expected the local image ID to be unknown only when replacing, got %s", planned)
+			}
+		})
+	}
+}
+
+func TestSnapshotResourceCheckSandboxLimits(t *testing.T) {
+	organization := &apiclient.Organization{MaxCpuPerSandbox: 4, MaxMemoryPerSandbox: 8, MaxDiskPerSandbox: 10}
+
+	tests := map[string]struct {
+		organization *apiclient.Organization
+		cpu          int32
+		expected     []string
+	}{
+		"within limits":     {organization: organization, cpu: 4},
+		"exceeding limits":  {organization: organization, cpu: 8, expected: []string{"Sandbox Limits Exceeded"}},
+		"unreadable limits": {cpu: 8},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeSnapshotAPI("registry.example.com")
+			api.organization = test.organization
+			r := newTestSnapshotResource(api, nil)
+
+			model := newSnapshotModel("app")
+			model.ImageName = types.StringValue("app:1.0")
+			model.Cpu = types.Int32Value(test.cpu)
+			model.Memory = types.Int32Value(1)
+			model.Disk = types.Int32Value(3)
+
+			resp := modifySnapshotPlan(t, r, snapshotPlan(t, model), nullSnapshotState(t))
+			requireErrors(t, resp.Diagnostics, test.expected...)
+		})
+	}
+}
+
+func TestCheckDeletionProtection(t *testing.T) {
+	state := newSnapshotModel("app")
+	requireNoErrors(t, checkDeletionProtection(state))
+
+	state.DeletionProtection = types.BoolValue(true)
+	requireErrors(t, checkDeletionProtection(state), "Snapshot Deletion Protected")
+}
+
+func TestSnapshotResourceDeleteProtected(t *testing.T) {
+	api := newFakeSnapshotAPI("registry.example.com")
+	api.snapshots["snapshot-app"] = &apiclient.SnapshotDto{Id: "snapshot-app", Name: "app"}
+	r := newTestSnapshotResource(api, nil)
+
+	model := newSnapshotModel("app")
+	model.Id = types.StringValue("snapshot-app")
+	model.DeletionProtection = types.BoolValue(true)
+	state := snapshotState(t, model)
+
+	resp := &resource.DeleteResponse{State: state}
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, resp)
+
+	requireErrors(t, resp.Diagnostics, "Snapshot Deletion Protected")
+	if len(api.calls) != 0 {
+		t.Errorf("expected the protected snapshot to be kept, got calls %v", api.calls)
+	}
+}
+
+func TestSnapshotResourceCheckReplacementName(t *testing.T) {
+	state := newSnapshotModel("app")
+	state.Id = types.StringValue("snapshot-app")
+	state.RemoteImageName = types.StringValue("registry.example.com/project/app:1")
+	state.Cpu = types.Int32Value(1)
+
+	tests := map[string]struct {
+		destroyBehavior string
+		name            string
+		cpu             int32
+		expected        []string
+	}{
+		"retained under the same name":    {destroyBehavior: service.DestroyBehaviorRetain, name: "app", cpu: 2, expected: []string{"Snapshot Name Conflict"}},
+		"deactivated under the same name": {destroyBehavior: service.DestroyBehaviorDeactivate, name: "app", cpu: 2, expected: []string{"Snapshot Name Conflict"}},
+		"retained under another name":     {destroyBehavior: service.DestroyBehaviorRetain, name: "app-v2", cpu: 2},
+		"deleted":                         {destroyBehavior: service.DestroyBehaviorDelete, name: "app", cpu: 2},
+		"retained without replacement":    {destroyBehavior: service.DestroyBehaviorRetain, name: "app", cpu: 1},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prior := state
+			prior.DestroyBehavior = types.StringValue(test.destroyBehavior)
+			planned := prior
+			planned.Name = types.StringValue(test.name)
+			planned.Cpu = types.Int32Value(test.cpu)
+
+			resp := modifySnapshotPlan(t, &SnapshotResource{}, snapshotPlan(t, planned), snapshotState(t, prior))
+			requireErrors(t, resp.Diagnostics, test.expected...)
+		})
+	}
+}
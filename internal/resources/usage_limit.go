@@ -0,0 +1,210 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+var _ resource.Resource = &UsageLimitResource{}
+
+func NewUsageLimitResource() resource.Resource {
+	return &UsageLimitResource{}
+}
+
+type UsageLimitResource struct {
+	client *daytona.Client
+}
+
+type UsageLimitResourceModel struct {
+	Id                  types.String  `tfsdk:"id"`
+	TotalCpu            types.Float32 `tfsdk:"total_cpu"`
+	TotalMemory         types.Float32 `tfsdk:"total_memory"`
+	TotalDisk           types.Float32 `tfsdk:"total_disk"`
+	MaxCpuPerSandbox    types.Float32 `tfsdk:"max_cpu_per_sandbox"`
+	MaxMemoryPerSandbox types.Float32 `tfsdk:"max_memory_per_sandbox"`
+	MaxDiskPerSandbox   types.Float32 `tfsdk:"max_disk_per_sandbox"`
+}
+
+func (r *UsageLimitResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_usage_limit"
+}
+
+func (r *UsageLimitResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the resource quotas of the provider's organization. Limits that aren't set are left as they are. Updating quotas usually requires Daytona admin permissions. Destroying the resource only removes it from the Terraform state, the quotas stay in place",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the organization",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"total_cpu": schema.Float32Attribute{
+				MarkdownDescription: "Total CPU cores all sandboxes of the organization can use",
+				Optional:            true,
+				Computed:            true,
+			},
+			"total_memory": schema.Float32Attribute{
+				MarkdownDescription: "Total memory in GB all sandboxes of the organization can use",
+				Optional:            true,
+				Computed:            true,
+			},
+			"total_disk": schema.Float32Attribute{
+				MarkdownDescription: "Total disk space in GB all sandboxes of the organization can use",
+				Optional:            true,
+				Computed:            true,
+			},
+			"max_cpu_per_sandbox": schema.Float32Attribute{
+				MarkdownDescription: "Maximum CPU cores of a single sandbox",
+				Optional:            true,
+				Computed:            true,
+			},
+			"max_memory_per_sandbox": schema.Float32Attribute{
+				MarkdownDescription: "Maximum memory in GB of a single sandbox",
+				Optional:            true,
+				Computed:            true,
+			},
+			"max_disk_per_sandbox": schema.Float32Attribute{
+				MarkdownDescription: "Maximum disk space in GB of a single sandbox",
+				Optional:            true,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *UsageLimitResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*daytona.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *daytona.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *UsageLimitResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *UsageLimitResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.updateQuota(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UsageLimitResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *UsageLimitResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organization, httpResp, err := r.client.OrganizationsAPI.GetOrganization(ctx, r.client.OrganizationID).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read organization, got error: %s", err))
+		return
+	}
+
+	data.setOrganization(organization)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UsageLimitResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *UsageLimitResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.updateQuota(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UsageLimitResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Info(ctx, "Removing usage limits from state, the organization quotas are kept", map[string]any{
+		"organization_id": r.client.OrganizationID,
+	})
+}
+
+// updateQuota sends the known limits, the API leaves null ones unchanged,
+// and fills in the model from the updated organization.
+func (r *UsageLimitResource) updateQuota(ctx context.Context, data *UsageLimitResourceModel) (diags diag.Diagnostics) {
+	updateRequest := apiclient.NewUpdateOrganizationQuota(
+		quotaValue(data.TotalCpu),
+		quotaValue(data.TotalMemory),
+		quotaValue(data.TotalDisk),
+		quotaValue(data.MaxCpuPerSandbox),
+		quotaValue(data.MaxMemoryPerSandbox),
+		quotaValue(data.MaxDiskPerSandbox),
+		*apiclient.NewNullableFloat32(nil),
+		*apiclient.NewNullableFloat32(nil),
+		*apiclient.NewNullableFloat32(nil),
+	)
+
+	organization, httpResp, err := r.client.OrganizationsAPI.UpdateOrganizationQuota(ctx, r.client.OrganizationID).UpdateOrganizationQuota(*updateRequest).Execute()
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to update organization quota, got error: %s", err))
+		return
+	}
+
+	data.setOrganization(organization)
+	return
+}
+
+func quotaValue(value types.Float32) apiclient.NullableFloat32 {
+	if value.IsNull() || value.IsUnknown() {
+		return *apiclient.NewNullableFloat32(nil)
+	}
+	return *apiclient.NewNullableFloat32(value.ValueFloat32Pointer())
+}
+
+// setOrganization fills in the limits reported by the API.
+func (m *UsageLimitResourceModel) setOrganization(organization *apiclient.Organization) {
+	m.Id = types.StringValue(organization.Id)
+	m.TotalCpu = types.Float32Value(organization.TotalCpuQuota)
+	m.TotalMemory = types.Float32Value(organization.TotalMemoryQuota)
+	m.TotalDisk = types.Float32Value(organization.TotalDiskQuota)
+	m.MaxCpuPerSandbox = types.Float32Value(organization.MaxCpuPerSandbox)
+	m.MaxMemoryPerSandbox = types.Float32Value(organization.MaxMemoryPerSandbox)
+	m.MaxDiskPerSandbox = types.Float32Value(organization.MaxDiskPerSandbox)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+// ErrNotFound is returned by DaytonaAPI lookups and deletions when the
+// requested object doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// DaytonaAPI is the part of the Daytona API the snapshot lifecycle needs.
+type DaytonaAPI interface {
+	GetSnapshot(ctx context.Context, idOrName string) (*apiclient.SnapshotDto, error)
+	ListSnapshots(ctx context.Context) ([]apiclient.SnapshotDto, error)
+	CreateSnapshot(ctx context.Context, createRequest apiclient.CreateSnapshot) (*apiclient.SnapshotDto, error)
+	RemoveSnapshot(ctx context.Context, id string) error
+	DeactivateSnapshot(ctx context.Context, id string) error
+	GetSnapshotBuildLogs(ctx context.Context, id string) (string, error)
+	GetTransientPushAccess(ctx context.Context) (*apiclient.RegistryPushAccessDto, error)
+	GetOrganization(ctx context.Context) (*apiclient.Organization, error)
+	CreateSandbox(ctx context.Context, createRequest apiclient.CreateSandbox) (*apiclient.Sandbox, error)
+	GetSandbox(ctx context.Context, id string) (*apiclient.Sandbox, error)
+	DeleteSandbox(ctx context.Context, id string) error
+	ExecuteCommand(ctx context.Context, sandboxID, command string) (*apiclient.ExecuteResponse, error)
+}
+
+// DockerAPI is the part of the Docker client used to source and push images.
+// *client.Client implements it.
+type DockerAPI interface {
+	ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
+	ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error)
+	ImageLoad(ctx context.Context, input io.Reader, quiet bool) (image.LoadResponse, error)
+	ImagePull(ctx context.Context, ref string, options image.PullOptions) (io.ReadCloser, error)
+	ImageTag(ctx context.Context, image, ref string) error
+	ImagePush(ctx context.Context, ref string, options image.PushOptions) (io.ReadCloser, error)
+	ImageRemove(ctx context.Context, image string, options image.RemoveOptions) ([]image.DeleteResponse, error)
+	ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error)
+	DistributionInspect(ctx context.Context, image, encodedRegistryAuth string) (registry.DistributionInspect, error)
+	ServerVersion(ctx context.Context) (types.Version, error)
+	Close() error
+}
+
+var _ DockerAPI = &client.Client{}
+
+// NewDockerClient connects to the Docker daemon selected by config, or else
+// by the environment. Podman and other engines serving the Docker API work the
+// same way.
+func NewDockerClient(config daytona.DockerConfig) (DockerAPI, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+
+	if config.TLS != nil {
+		opts = append(opts, client.WithHTTPClient(&http.Client{
+			Transport: &http.Transport{TLSClientConfig: config.TLS},
+		}))
+	}
+
+	// the host is always set, a replaced transport has to be set up for it
+	// again
+	host := config.Host
+	if host == "" {
+		host = os.Getenv(client.EnvOverrideHost)
+	}
+	if host == "" {
+		host = containerHost()
+	}
+	if host == "" {
+		host = defaultDockerHost()
+	}
+	opts = append(opts, client.WithHost(host))
+
+	return client.NewClientWithOpts(opts...)
+}
+
+// containerHost returns the CONTAINER_HOST Podman's remote client connects
+// to, unless it connects over ssh.
+func containerHost() string {
+	host := os.Getenv("CONTAINER_HOST")
+	if strings.HasPrefix(host, "ssh://") {
+		return ""
+	}
+	return host
+}
+
+// defaultDockerHost returns the local Docker socket, or Podman's if only
+// Podman is running.
+func defaultDockerHost() string {
+	socket, isUnix := strings.CutPrefix(client.DefaultDockerHost, "unix://")
+	if !isUnix {
+		return client.DefaultDockerHost
+	}
+	if _, err := os.Stat(socket); err == nil {
+		return client.DefaultDockerHost
+	}
+
+	for _, socket := range podmanSockets() {
+		if _, err := os.Stat(socket); err == nil {
+			return "unix://" + socket
+		}
+	}
+	return client.DefaultDockerHost
+}
+
+// podmanSockets are where Podman serves the Docker API, rootless and as root.
+func podmanSockets() []string {
+	var sockets []string
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		sockets = append(sockets, filepath.Join(runtimeDir, "podman", "podman.sock"))
+	}
+	return append(sockets, "/run/podman/podman.sock")
+}
+
+var _ DaytonaAPI = &daytonaAPI{}
+
+type daytonaAPI struct {
+	client *daytona.Client
+}
+
+// NewDaytonaAPI wraps the generated API client.
+func NewDaytonaAPI(client *daytona.Client) DaytonaAPI {
+	return &daytonaAPI{client: client}
+}
+
+func (a *daytonaAPI) GetSnapshot(ctx context.Context, idOrName string) (*apiclient.SnapshotDto, error) {
+	snapshot, httpResp, err := a.client.SnapshotsAPI.GetSnapshot(ctx, idOrName).Execute()
+	return snapshot, checkResponse(httpResp, err)
+}
+
+// snapshotPageSize is the number of snapshots fetched per page when listing.
+const snapshotPageSize = 100
+
+func (a *daytonaAPI) ListSnapshots(ctx context.Context) ([]apiclient.SnapshotDto, error) {
+	var snapshots []apiclient.SnapshotDto
+
+	for page := 1; ; page++ {
+		paginated, httpResp, err := a.client.SnapshotsAPI.GetAllSnapshots(ctx).Page(float32(page)).Limit(snapshotPageSize).Execute()
+		if err = checkResponse(httpResp, err); err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, paginated.Items...)
+		if float32(page) >= paginated.TotalPages {
+			return snapshots, nil
+		}
+	}
+}
+
+func (a *daytonaAPI) CreateSnapshot(ctx context.Context, createRequest apiclient.CreateSnapshot) (*apiclient.SnapshotDto, error) {
+	snapshot, httpResp, err := a.client.SnapshotsAPI.CreateSnapshot(ctx).CreateSnapshot(createRequest).Execute()
+	return snapshot, checkResponse(httpResp, err)
+}
+
+func (a *daytonaAPI) RemoveSnapshot(ctx context.Context, id string) error {
+	httpResp, err := a.client.SnapshotsAPI.RemoveSnapshot(ctx, id).Execute()
+	return checkResponse(httpResp, err)
+}
+
+func (a *daytonaAPI) DeactivateSnapshot(ctx context.Context, id string) error {
+	httpResp, err := a.client.SnapshotsAPI.DeactivateSnapshot(ctx, id).Execute()
+	return checkResponse(httpResp, err)
+}
+
+func (a *daytonaAPI) GetSnapshotBuildLogs(ctx context.Context, id string) (string, error) {
+	httpResp, err := a.client.SnapshotsAPI.GetSnapshotBuildLogs(ctx, id).Execute()
+	if err = checkResponse(httpResp, err); err != nil {
+		return "", err
+	}
+	defer httpResp.Body.Close()
+
+	logs, err := io.ReadAll(httpResp.Body)
+	return string(logs), err
+}
+
+func (a *daytonaAPI) GetTransientPushAccess(ctx context.Context) (*apiclient.RegistryPushAccessDto, error) {
+	return a.client.TransientPushAccess(ctx)
+}
+
+func (a *daytonaAPI) GetOrganization(ctx context.Context) (*apiclient.Organization, error) {
+	organization, httpResp, err := a.client.OrganizationsAPI.GetOrganization(ctx, a.client.OrganizationID).Execute()
+	return organization, checkResponse(httpResp, err)
+}
+
+func (a *daytonaAPI) CreateSandbox(ctx context.Context, createRequest apiclient.CreateSandbox) (*apiclient.Sandbox, error) {
+	sandbox, httpResp, err := a.client.SandboxAPI.CreateSandbox(ctx).CreateSandbox(createRequest).Execute()
+	return sandbox, checkResponse(httpResp, err)
+}
+
+func (a *daytonaAPI) GetSandbox(ctx context.Context, id string) (*apiclient.Sandbox, error) {
+	sandbox, httpResp, err := a.client.SandboxAPI.GetSandbox(ctx, id).Execute()
+	return sandbox, checkResponse(httpResp, err)
+}
+
+func (a *daytonaAPI) DeleteSandbox(ctx context.Context, id string) error {
+	httpResp, err := a.client.SandboxAPI.DeleteSandbox(ctx, id).Force(true).Execute()
+	return checkResponse(httpResp, err)
+}
+
+func (a *daytonaAPI) ExecuteCommand(ctx context.Context, sandboxID, command string) (*apiclient.ExecuteResponse, error) {
+	result, httpResp, err := a.client.ToolboxAPI.ExecuteCommand(ctx, sandboxID).ExecuteRequest(*apiclient.NewExecuteRequest(command)).Execute()
+	return result, checkResponse(httpResp, err)
+}
+
+// checkResponse closes the response body and maps 404 responses to
+// ErrNotFound.
+func checkResponse(httpResp *http.Response, err error) error {
+	if httpResp != nil && httpResp.Body != nil {
+		httpResp.Body.Close()
+	}
+	if err != nil && httpResp != nil && httpResp.StatusCode == 404 {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestPodmanSockets(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+
+	expected := []string{filepath.Join(runtimeDir, "podman", "podman.sock"), "/run/podman/podman.sock"}
+	if sockets := podmanSockets(); !slices.Equal(sockets, expected) {
+		t.Errorf("unexpected sockets %v", sockets)
+	}
+
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	if sockets := podmanSockets(); !slices.Equal(sockets, expected[1:]) {
+		t.Errorf("unexpected sockets without runtime dir %v", sockets)
+	}
+}
+
+func TestDefaultDockerHostFindsPodman(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Podman's socket is a named pipe on Windows")
+	}
+	if _, err := os.Stat("/var/run/docker.sock"); err == nil {
+		t.Skip("Docker is running")
+	}
+
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	socket := filepath.Join(runtimeDir, "podman", "podman.sock")
+	if err := os.MkdirAll(filepath.Dir(socket), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(socket, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if host := defaultDockerHost(); host != "unix://"+socket {
+		t.Errorf("unexpected host %q", host)
+	}
+}
+
+func TestContainerHost(t *testing.T) {
+	t.Setenv("CONTAINER_HOST", "unix:///run/user/1000/podman/podman.sock")
+	if host := containerHost(); host != "unix:///run/user/1000/podman/podman.sock" {
+		t.Errorf("unexpected host %q", host)
+	}
+
+	t.Setenv("CONTAINER_HOST", "ssh://core@localhost:2222/run/podman/podman.sock")
+	if host := containerHost(); host != "" {
+		t.Errorf("ssh host should be ignored, got %q", host)
+	}
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// buildOutputLines is how many of the last lines of build output are
+// included in build errors.
+const buildOutputLines = 20
+
+// BuildSpec describes how to build an image with the local Docker daemon.
+type BuildSpec struct {
+	// Context is the directory sent to the daemon as build context
+	Context string
+	// Dockerfile is the path of the Dockerfile within the context, defaulting
+	// to "Dockerfile"
+	Dockerfile string
+	// Target is the build stage to build, defaulting to the last one
+	Target string
+}
+
+// BuildContextHash hashes the files of a build context that are sent to the
+// Docker daemon, so a build can be skipped when none of them changed. Files
+// excluded by .dockerignore don't affect it.
+func BuildContextHash(spec BuildSpec) (string, error) {
+	contextDir := spec.Context
+
+	files, err := buildContextFiles(spec)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	for _, name := range files {
+		info, err := os.Lstat(filepath.Join(contextDir, name))
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(hash, "%s\x00%o\x00", filepath.ToSlash(name), info.Mode())
+		switch {
+		case info.Mode().IsRegular():
+			file, err := os.Open(filepath.Join(contextDir, name))
+			if err != nil {
+				return "", err
+			}
+			_, err = io.Copy(hash, file)
+			file.Close()
+			if err != nil {
+				return "", err
+			}
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(filepath.Join(contextDir, name))
+			if err != nil {
+				return "", err
+			}
+			io.WriteString(hash, target)
+		}
+		hash.Write([]byte{0})
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// buildImage builds the image from its build context for the platform, if
+// any, and tags it as imageName.
+func buildImage(ctx context.Context, dockerClient DockerAPI, imageName string, spec BuildSpec, platform string) error {
+	files, err := buildContextFiles(spec)
+	if err != nil {
+		return fmt.Errorf("unable to read build context: %w", err)
+	}
+
+	contextReader, contextWriter := io.Pipe()
+	go func() {
+		contextWriter.CloseWithError(writeBuildContext(contextWriter, spec.Context, files))
+	}()
+	defer contextReader.Close()
+
+	tflog.Info(ctx, "Building image", map[string]any{
+		"image_name":    imageName,
+		"build_context": spec.Context,
+		"dockerfile":    spec.Dockerfile,
+		"target":        spec.Target,
+		"platform":      platform,
+	})
+
+	buildResp, err := dockerClient.ImageBuild(ctx, contextReader, types.ImageBuildOptions{
+		Tags:        []string{imageName},
+		Dockerfile:  spec.Dockerfile,
+		Target:      spec.Target,
+		Platform:    platform,
+		Remove:      true,
+		ForceRemove: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer buildResp.Body.Close()
+
+	// the daemon reports build failures in the message stream
+	decoder := json.NewDecoder(buildResp.Body)
+	var output []string
+	for {
+		var message struct {
+			Stream string `json:"stream"`
+			Error  string `json:"error"`
+		}
+		if err := decoder.Decode(&message); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("unable to read build output: %w", err)
+		}
+
+		if message.Error != "" {
+			// the last lines of output usually explain the failure
+			return fmt.Errorf("%s\n%s", message.Error, strings.Join(output[max(0, len(output)-buildOutputLines):], ""))
+		}
+		if message.Stream != "" {
+			output = append(output, message.Stream)
+		}
+	}
+}
+
+// writeBuildContext writes the files of the build context as tar stream.
+func writeBuildContext(writer io.Writer, contextDir string, files []string) error {
+	tarWriter := tar.NewWriter(writer)
+
+	for _, name := range files {
+		path := filepath.Join(contextDir, name)
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		// keep the context reproducible between machines
+		header.Uname, header.Gname = "", ""
+		header.Uid, header.Gid = 0, 0
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(tarWriter, file)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tarWriter.Close()
+}
+
+// buildContextFiles lists the files and directories of the build context
+// relative to it, sorted and without the ones excluded by .dockerignore. Like
+// with the Docker CLI, the Dockerfile and .dockerignore are always included.
+func buildContextFiles(spec BuildSpec) ([]string, error) {
+	contextDir := spec.Context
+
+	patterns, err := readDockerignore(contextDir)
+	if err != nil {
+		return nil, err
+	}
+
+	dockerfile := spec.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	alwaysIncluded := []string{filepath.ToSlash(filepath.Clean(dockerfile)), ".dockerignore"}
+
+	var files []string
+	err = filepath.WalkDir(contextDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(contextDir, path)
+		if err != nil || name == "." {
+			return err
+		}
+
+		if excluded(patterns, filepath.ToSlash(name)) && !slices.Contains(alwaysIncluded, filepath.ToSlash(name)) {
+			// excluded directories may still contain re-included files,
+			// so only skip them when there are no exceptions
+			if entry.IsDir() && !slices.ContainsFunc(patterns, func(p ignorePattern) bool { return p.exception }) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		files = append(files, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(files)
+	return files, nil
+}
+
+// ignorePattern is a line of .dockerignore.
+type ignorePattern struct {
+	regexp    *regexp.Regexp
+	exception bool
+}
+
+// readDockerignore parses the .dockerignore of the build context. Patterns
+// follow Docker's syntax: `*` and `?` don't match `/`, `**` matches any number
+// of directories, and lines starting with `!` re-include files.
+func readDockerignore(contextDir string) ([]ignorePattern, error) {
+	file, err := os.Open(filepath.Join(contextDir, ".dockerignore"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var patterns []ignorePattern
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var pattern ignorePattern
+		if strings.HasPrefix(line, "!") {
+			pattern.exception = true
+			line = strings.TrimSpace(line[1:])
+		}
+
+		line = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(line)), "/")
+		pattern.regexp, err = ignorePatternRegexp(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid .dockerignore pattern %q: %w", line, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns, scanner.Err()
+}
+
+func ignorePatternRegexp(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	// a pattern matching a directory excludes everything within it
+	expr.WriteString("(/.*)?$")
+	return regexp.Compile(expr.String())
+}
+
+// excluded reports whether the last matching pattern excludes the file.
+func excluded(patterns []ignorePattern, name string) bool {
+	var matched bool
+	for _, pattern := range patterns {
+		if pattern.regexp.MatchString(name) {
+			matched = !pattern.exception
+		}
+	}
+	return matched
+}
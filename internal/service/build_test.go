@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPushImageBuildsContext(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Dockerfile":             "FROM ubuntu\nCOPY . /app",
+		".dockerignore":          "# build output\n**/*.log\nnode_modules\n!node_modules/keep.txt\nDockerfile",
+		"main.go":                "package main",
+		"pkg/debug.log":          "noise",
+		"node_modules/dep.js":    "dep",
+		"node_modules/keep.txt":  "keep",
+		"pkg/nested/service.txt": "service",
+	})
+
+	docker := newFakeDocker()
+	s := newTestService(newFakeAPI(), docker)
+
+	_, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:latest",
+		ImageSources: []string{ImageSourceLocal},
+		Build:        &BuildSpec{Context: dir, Target: "runtime"},
+	})
+	requireNoErrors(t, errs)
+
+	if !slices.Contains(docker.calls, "ImageBuild app:latest  runtime") {
+		t.Errorf("image wasn't built, calls: %v", docker.calls)
+	}
+
+	expected := []string{".dockerignore", "Dockerfile", "main.go", "node_modules/keep.txt", "pkg", "pkg/nested", "pkg/nested/service.txt"}
+	if !slices.Equal(docker.buildContext, expected) {
+		t.Errorf("unexpected build context %v, expected %v", docker.buildContext, expected)
+	}
+}
+
+func TestPushImageBuildFailure(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"Dockerfile": "FROM ubuntu\nRUN false"})
+
+	docker := newFakeDocker()
+	docker.buildError = "The command '/bin/sh -c false' returned a non-zero code: 1"
+	s := newTestService(newFakeAPI(), docker)
+
+	_, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName: "app:latest",
+		Build:     &BuildSpec{Context: dir},
+	})
+	requireError(t, errs, "RUN false")
+
+	if slices.ContainsFunc(docker.calls, func(call string) bool { return call != "ImageBuild app:latest  " }) {
+		t.Errorf("failed build shouldn't be pushed, calls: %v", docker.calls)
+	}
+}
+
+func TestBuildContextHash(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Dockerfile":    "FROM ubuntu",
+		".dockerignore": "*.log",
+		"main.go":       "package main",
+	})
+	spec := BuildSpec{Context: dir}
+
+	hash := func() string {
+		t.Helper()
+		hash, err := BuildContextHash(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	initial := hash()
+
+	writeFiles(t, dir, map[string]string{"debug.log": "ignored"})
+	if hash() != initial {
+		t.Errorf("ignored files shouldn't change the hash")
+	}
+
+	writeFiles(t, dir, map[string]string{"main.go": "package main\n"})
+	if hash() == initial {
+		t.Errorf("changed files should change the hash")
+	}
+}
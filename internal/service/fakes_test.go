@@ -0,0 +1,388 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+)
+
+// fakeAPI is an in-memory Daytona API. Snapshots and sandboxes go through a
+// configurable number of pending polls before reaching their final state.
+type fakeAPI struct {
+	snapshots map[string]*apiclient.SnapshotDto
+	sandboxes map[string]*apiclient.Sandbox
+	// polls left before a snapshot or sandbox reaches its final state
+	pending map[string]int
+
+	snapshotFinalState apiclient.SnapshotState
+	sandboxFinalState  apiclient.SandboxState
+	exitCode           float32
+	// removals that take effect only after this many lookups
+	removalDelay int
+	// buildLogs are returned for every snapshot
+	buildLogs string
+	// registryURL overrides the registry of the push access
+	registryURL string
+	// organization is returned by GetOrganization
+	organization apiclient.Organization
+
+	removing map[string]int
+	calls    []string
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		snapshots:          map[string]*apiclient.SnapshotDto{},
+		sandboxes:          map[string]*apiclient.Sandbox{},
+		pending:            map[string]int{},
+		removing:           map[string]int{},
+		snapshotFinalState: apiclient.SNAPSHOTSTATE_ACTIVE,
+		sandboxFinalState:  apiclient.SANDBOXSTATE_STARTED,
+		organization: apiclient.Organization{
+			MaxCpuPerSandbox:    4,
+			MaxMemoryPerSandbox: 8,
+			MaxDiskPerSandbox:   10,
+		},
+	}
+}
+
+func (f *fakeAPI) record(format string, args ...any) {
+	f.calls = append(f.calls, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeAPI) addSnapshot(id, name string, state apiclient.SnapshotState) {
+	f.snapshots[id] = &apiclient.SnapshotDto{
+		Id:        id,
+		Name:      name,
+		State:     state,
+		CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (f *fakeAPI) findSnapshot(idOrName string) *apiclient.SnapshotDto {
+	for _, snapshot := range f.snapshots {
+		if snapshot.Id == idOrName || snapshot.Name == idOrName {
+			return snapshot
+		}
+	}
+	return nil
+}
+
+func (f *fakeAPI) GetSnapshot(ctx context.Context, idOrName string) (*apiclient.SnapshotDto, error) {
+	snapshot := f.findSnapshot(idOrName)
+	if snapshot == nil {
+		return nil, ErrNotFound
+	}
+
+	if left, ok := f.removing[snapshot.Id]; ok {
+		if left == 0 {
+			delete(f.snapshots, snapshot.Id)
+			delete(f.removing, snapshot.Id)
+			return nil, ErrNotFound
+		}
+		f.removing[snapshot.Id] = left - 1
+	}
+
+	if left := f.pending[snapshot.Id]; left > 0 {
+		f.pending[snapshot.Id] = left - 1
+	} else if snapshot.State == apiclient.SNAPSHOTSTATE_PENDING {
+		snapshot.State = f.snapshotFinalState
+		if snapshot.State != apiclient.SNAPSHOTSTATE_ACTIVE {
+			snapshot.SetErrorReason("build exploded")
+		}
+	}
+
+	copied := *snapshot
+	return &copied, nil
+}
+
+func (f *fakeAPI) ListSnapshots(ctx context.Context) ([]apiclient.SnapshotDto, error) {
+	f.record("ListSnapshots")
+
+	var snapshots []apiclient.SnapshotDto
+	for _, snapshot := range f.snapshots {
+		snapshots = append(snapshots, *snapshot)
+	}
+	return snapshots, nil
+}
+
+func (f *fakeAPI) CreateSnapshot(ctx context.Context, createRequest apiclient.CreateSnapshot) (*apiclient.SnapshotDto, error) {
+	if createRequest.BuildInfo != nil {
+		f.record("CreateSnapshot %s build %s %s", createRequest.Name, createRequest.BuildInfo.DockerfileContent, strings.Join(createRequest.BuildInfo.ContextHashes, ","))
+	} else {
+		f.record("CreateSnapshot %s %s", createRequest.Name, createRequest.GetImageName())
+	}
+
+	id := "snapshot-" + createRequest.Name
+	f.addSnapshot(id, createRequest.Name, apiclient.SNAPSHOTSTATE_PENDING)
+	f.snapshots[id].ImageName = createRequest.ImageName
+	f.snapshots[id].Entrypoint = createRequest.Entrypoint
+	if createRequest.Cpu != nil {
+		f.snapshots[id].Cpu = float32(*createRequest.Cpu)
+	}
+	if createRequest.Gpu != nil {
+		f.snapshots[id].Gpu = float32(*createRequest.Gpu)
+	}
+	f.pending[id] = 2
+
+	copied := *f.snapshots[id]
+	return &copied, nil
+}
+
+func (f *fakeAPI) RemoveSnapshot(ctx context.Context, id string) error {
+	f.record("RemoveSnapshot %s", id)
+
+	if _, ok := f.snapshots[id]; !ok {
+		return ErrNotFound
+	}
+	f.removing[id] = f.removalDelay
+	return nil
+}
+
+func (f *fakeAPI) DeactivateSnapshot(ctx context.Context, id string) error {
+	f.record("DeactivateSnapshot %s", id)
+
+	snapshot, ok := f.snapshots[id]
+	if !ok {
+		return ErrNotFound
+	}
+	snapshot.State = apiclient.SNAPSHOTSTATE_INACTIVE
+	return nil
+}
+
+func (f *fakeAPI) GetSnapshotBuildLogs(ctx context.Context, id string) (string, error) {
+	f.record("GetSnapshotBuildLogs %s", id)
+	return f.buildLogs, nil
+}
+
+func (f *fakeAPI) GetOrganization(ctx context.Context) (*apiclient.Organization, error) {
+	organization := f.organization
+	return &organization, nil
+}
+
+func (f *fakeAPI) GetTransientPushAccess(ctx context.Context) (*apiclient.RegistryPushAccessDto, error) {
+	registryURL := f.registryURL
+	if registryURL == "" {
+		registryURL = "registry.example.com"
+	}
+	return &apiclient.RegistryPushAccessDto{
+		Username:    "user",
+		Secret:      "secret",
+		RegistryUrl: registryURL,
+		Project:     "project",
+	}, nil
+}
+
+func (f *fakeAPI) CreateSandbox(ctx context.Context, createRequest apiclient.CreateSandbox) (*apiclient.Sandbox, error) {
+	f.record("CreateSandbox %s", createRequest.GetSnapshot())
+
+	id := "sandbox-" + createRequest.GetSnapshot()
+	f.sandboxes[id] = &apiclient.Sandbox{Id: id}
+	f.sandboxes[id].SetState(apiclient.SANDBOXSTATE_CREATING)
+	f.pending[id] = 1
+
+	copied := *f.sandboxes[id]
+	return &copied, nil
+}
+
+func (f *fakeAPI) GetSandbox(ctx context.Context, id string) (*apiclient.Sandbox, error) {
+	sandbox, ok := f.sandboxes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if left := f.pending[id]; left > 0 {
+		f.pending[id] = left - 1
+	} else {
+		sandbox.SetState(f.sandboxFinalState)
+		if f.sandboxFinalState != apiclient.SANDBOXSTATE_STARTED {
+			sandbox.SetErrorReason("no capacity")
+		}
+	}
+
+	copied := *sandbox
+	return &copied, nil
+}
+
+func (f *fakeAPI) DeleteSandbox(ctx context.Context, id string) error {
+	f.record("DeleteSandbox %s", id)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, ok := f.sandboxes[id]; !ok {
+		return ErrNotFound
+	}
+	delete(f.sandboxes, id)
+	return nil
+}
+
+func (f *fakeAPI) ExecuteCommand(ctx context.Context, sandboxID, command string) (*apiclient.ExecuteResponse, error) {
+	f.record("ExecuteCommand %s %s", sandboxID, command)
+
+	return apiclient.NewExecuteResponse(f.exitCode, "output"), nil
+}
+
+// fakeDocker is an in-memory Docker daemon.
+type fakeDocker struct {
+	images   map[string]bool
+	pullable map[string]bool
+	// platforms of the images, linux/amd64 unless set
+	platforms map[string]string
+	calls     []string
+	// buildContext lists the files received by the last build
+	buildContext []string
+	buildError   string
+	// pushOutput is the message stream returned by pushes
+	pushOutput string
+	// apiVersion is the API version the engine reports
+	apiVersion string
+	// savedArchive is the archive ImageSave exports
+	savedArchive []byte
+}
+
+func newFakeDocker(images ...string) *fakeDocker {
+	docker := &fakeDocker{
+		images:     map[string]bool{},
+		pullable:   map[string]bool{},
+		platforms:  map[string]string{},
+		apiVersion: "1.47",
+	}
+	for _, name := range images {
+		docker.images[name] = true
+	}
+	return docker
+}
+
+func (d *fakeDocker) record(format string, args ...any) {
+	d.calls = append(d.calls, fmt.Sprintf(format, args...))
+}
+
+func (d *fakeDocker) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	d.record("ImageBuild %s %s %s", strings.Join(options.Tags, ","), options.Dockerfile, options.Target)
+
+	d.buildContext = nil
+	reader := tar.NewReader(buildContext)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return types.ImageBuildResponse{}, err
+		}
+		d.buildContext = append(d.buildContext, header.Name)
+	}
+
+	if d.buildError != "" {
+		return types.ImageBuildResponse{
+			Body: io.NopCloser(strings.NewReader(`{"stream":"Step 1/1 : RUN false\n"}` + fmt.Sprintf(`{"error":%q}`, d.buildError))),
+		}, nil
+	}
+
+	for _, tag := range options.Tags {
+		d.images[tag] = true
+	}
+	return types.ImageBuildResponse{
+		Body: io.NopCloser(strings.NewReader(`{"stream":"Successfully built\n"}`)),
+	}, nil
+}
+
+func (d *fakeDocker) ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error) {
+	if !d.images[image] {
+		return types.ImageInspect{}, nil, fmt.Errorf("no such image: %s", image)
+	}
+	platform, ok := d.platforms[image]
+	if !ok {
+		platform = "linux/amd64"
+	}
+	os, arch, _ := strings.Cut(platform, "/")
+	arch, variant, _ := strings.Cut(arch, "/")
+	return types.ImageInspect{ID: image, Os: os, Architecture: arch, Variant: variant}, nil, nil
+}
+
+func (d *fakeDocker) ImageLoad(ctx context.Context, input io.Reader, quiet bool) (image.LoadResponse, error) {
+	return image.LoadResponse{}, fmt.Errorf("loading archives is not supported")
+}
+
+func (d *fakeDocker) ImagePull(ctx context.Context, ref string, options image.PullOptions) (io.ReadCloser, error) {
+	d.record("ImagePull %s", ref)
+
+	if !d.pullable[ref] {
+		return nil, fmt.Errorf("pull access denied for %s", ref)
+	}
+	d.images[ref] = true
+	if options.Platform != "" {
+		d.platforms[ref] = options.Platform
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (d *fakeDocker) ImageTag(ctx context.Context, image, ref string) error {
+	d.record("ImageTag %s %s", image, ref)
+
+	d.images[ref] = true
+	return nil
+}
+
+func (d *fakeDocker) ImagePush(ctx context.Context, ref string, options image.PushOptions) (io.ReadCloser, error) {
+	if options.Platform != nil {
+		d.record("ImagePush %s %s", ref, platformName(options.Platform))
+	} else {
+		d.record("ImagePush %s", ref)
+	}
+
+	return io.NopCloser(strings.NewReader(d.pushOutput)), nil
+}
+
+func (d *fakeDocker) ImageRemove(ctx context.Context, image string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+	d.record("ImageRemove %s", image)
+
+	delete(d.images, image)
+	return nil, nil
+}
+
+func (d *fakeDocker) ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+	d.record("ImageSave %s", strings.Join(imageIDs, ","))
+
+	for _, image := range imageIDs {
+		if !d.images[image] {
+			return nil, fmt.Errorf("no such image: %s", image)
+		}
+	}
+	return io.NopCloser(bytes.NewReader(d.savedArchive)), nil
+}
+
+func (d *fakeDocker) DistributionInspect(ctx context.Context, image, encodedRegistryAuth string) (registry.DistributionInspect, error) {
+	d.record("DistributionInspect %s", image)
+
+	var distribution registry.DistributionInspect
+	distribution.Descriptor.Digest = "sha256:0123456789abcdef"
+	return distribution, nil
+}
+
+func (d *fakeDocker) ServerVersion(ctx context.Context) (types.Version, error) {
+	return types.Version{APIVersion: d.apiVersion}, nil
+}
+
+func (d *fakeDocker) Close() error {
+	return nil
+}
+
+func newTestService(api *fakeAPI, docker *fakeDocker) *Service {
+	return &Service{
+		API: api,
+		NewDocker: func() (DockerAPI, error) {
+			return docker, nil
+		},
+		PollInterval: time.Millisecond,
+	}
+}
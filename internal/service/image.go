@@ -0,0 +1,657 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	ImageSourceLocal    = "local"
+	ImageSourceArchive  = "archive"
+	ImageSourceRegistry = "registry"
+	// ImageSourceCopy copies the image from its remote registry into
+	// Daytona's registry with the distribution API, without a Docker daemon.
+	// It can't be combined with other sources.
+	ImageSourceCopy = "copy"
+)
+
+// pushPlatformAPIVersion is the Docker API version which added selecting the
+// platform to push.
+const pushPlatformAPIVersion = "1.46"
+
+// pushProgressInterval is how often the progress of a push is logged.
+const pushProgressInterval = 10 * time.Second
+
+// ImageSpec describes a local image and where to source it from.
+type ImageSpec struct {
+	ImageName    string
+	ImageSources []string
+	ImageArchive string
+	// KeepLocalTag leaves the remote tag in the Docker daemon after pushing
+	KeepLocalTag bool
+	// Build builds ImageName with the Docker daemon instead of sourcing it
+	Build *BuildSpec
+	// Platform selects the os/arch[/variant] of multi-platform images when
+	// building, pulling, copying and pushing them. Empty means the daemon's
+	// or registry's default
+	Platform string
+	// RemoteTag is the tag of the image in Daytona's registry, which may use
+	// the RemoteTagPlaceholders. Empty means the push's timestamp
+	RemoteTag string
+	// PushMode is PushModeDaemon or PushModeDirect. Empty means
+	// PushModeDaemon
+	PushMode string
+}
+
+// RemoteTagPlaceholders are expanded in ImageSpec.RemoteTag: the time of the
+// push, the tag of ImageName, and the start of the pushed image's digest.
+var RemoteTagPlaceholders = []string{"{timestamp}", "{tag}", "{digest}"}
+
+// remoteTagDigestLength is how many hex digits of the digest {digest} expands
+// to, like the short image IDs of the Docker CLI.
+const remoteTagDigestLength = 12
+
+// PushedImage is an image pushed into Daytona's registry.
+type PushedImage struct {
+	RemoteImageName string
+	Digest          string
+	// LocalImageID is the ID of the local image that was pushed
+	LocalImageID string
+}
+
+// SameImageReference reports whether two image references point to the same
+// image once normalized, e.g. `ubuntu`, `ubuntu:latest` and
+// `docker.io/library/ubuntu:latest`. References that can't be parsed are
+// compared as they are.
+func SameImageReference(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	normalizedA, err := normalizeImageReference(a)
+	if err != nil {
+		return false
+	}
+	normalizedB, err := normalizeImageReference(b)
+	if err != nil {
+		return false
+	}
+
+	return normalizedA == normalizedB
+}
+
+func normalizeImageReference(ref string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", err
+	}
+	return reference.TagNameOnly(named).String(), nil
+}
+
+// PushImage sources the image into the Docker daemon and pushes it into
+// Daytona's registry. The remote tag is removed from the daemon afterwards
+// unless it should be kept. Images with the copy source are copied between
+// the registries instead, and PushModeDirect uploads the image without the
+// daemon's push.
+func (s *Service) PushImage(ctx context.Context, spec ImageSpec) (pushed PushedImage, warns, errs diag.Diagnostics) {
+	platform, err := parsePlatform(spec.Platform)
+	if err != nil {
+		errs.AddError("Invalid Platform", err.Error())
+		return
+	}
+
+	if spec.Build == nil && slices.Contains(spec.ImageSources, ImageSourceCopy) {
+		if len(spec.ImageSources) > 1 {
+			errs.AddError("Invalid Image Sources", fmt.Sprintf("The %q image source can't be combined with other image sources", ImageSourceCopy))
+			return
+		}
+		return s.copyImageToRegistry(ctx, spec.ImageName, spec.RemoteTag, platform)
+	}
+
+	if spec.PushMode == PushModeDirect {
+		return s.pushDirect(ctx, spec, platform)
+	}
+
+	dockerClient, err := s.NewDocker()
+	if err != nil {
+		errs.AddError("Docker Client Error", fmt.Sprintf("Unable to create Docker client: %v", err))
+		return
+	}
+	defer dockerClient.Close()
+
+	localImage, errors := s.prepareLocalImage(ctx, dockerClient, spec, platform)
+	errs.Append(errors...)
+	if errs.HasError() {
+		return
+	}
+
+	tag, err := remoteTag(spec.RemoteTag, spec.ImageName, localImage.ID)
+	if err != nil {
+		errs.AddError("Invalid Remote Tag", err.Error())
+		return
+	}
+
+	pushed, warnings, errors := s.pushImageToRegistry(ctx, dockerClient, spec.ImageName, tag, platform)
+	warns.Append(warnings...)
+	errs.Append(errors...)
+	if errs.HasError() {
+		return
+	}
+	pushed.LocalImageID = localImage.ID
+
+	if spec.KeepLocalTag {
+		return
+	}
+
+	// we don't care too much about untagging. it's a garbage left behind, but not
+	// a real error that prevents us from continuing
+	_, err = dockerClient.ImageRemove(ctx, pushed.RemoteImageName, image.RemoveOptions{})
+	if err != nil {
+		warns.AddWarning("Cleanup Warning", fmt.Sprintf("Failed to remove tagged image %s: %v", pushed.RemoteImageName, err))
+	}
+
+	return
+}
+
+// prepareLocalImage builds or sources the image into the Docker daemon and
+// checks that it is built for the platform.
+func (s *Service) prepareLocalImage(ctx context.Context, dockerClient DockerAPI, spec ImageSpec, platform *ocispec.Platform) (localImage types.ImageInspect, errors diag.Diagnostics) {
+	if spec.Build != nil {
+		if err := buildImage(ctx, dockerClient, spec.ImageName, *spec.Build, platformName(platform)); err != nil {
+			errors.AddError("Build Error", fmt.Sprintf("Unable to build image %q: %v", spec.ImageName, err))
+			return
+		}
+	} else {
+		errors.Append(s.resolveLocalImage(ctx, dockerClient, spec, platform)...)
+		if errors.HasError() {
+			return
+		}
+	}
+
+	localImage, _, err := dockerClient.ImageInspectWithRaw(ctx, spec.ImageName)
+	if err != nil {
+		errors.AddError("Docker Error", fmt.Sprintf("Unable to inspect image %q: %v", spec.ImageName, err))
+		return
+	}
+	if err := checkImagePlatform(localImage, platform); err != nil {
+		errors.AddError("Platform Mismatch", fmt.Sprintf("Image %q can't be pushed: %v", spec.ImageName, err))
+		return
+	}
+	return
+}
+
+// LocalImageID returns the ID of an image in the Docker daemon.
+func (s *Service) LocalImageID(ctx context.Context, imageName string) (string, error) {
+	dockerClient, err := s.NewDocker()
+	if err != nil {
+		return "", err
+	}
+	defer dockerClient.Close()
+
+	localImage, _, err := dockerClient.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return "", err
+	}
+
+	return localImage.ID, nil
+}
+
+// resolveLocalImage makes sure the image is present in the Docker daemon,
+// trying each configured source in order until one provides it for the
+// platform.
+func (s *Service) resolveLocalImage(ctx context.Context, dockerClient DockerAPI, spec ImageSpec, platform *ocispec.Platform) (errors diag.Diagnostics) {
+	var failures []string
+
+	for _, source := range spec.ImageSources {
+		var err error
+
+		switch source {
+		case ImageSourceLocal:
+			_, _, err = dockerClient.ImageInspectWithRaw(ctx, spec.ImageName)
+		case ImageSourceArchive:
+			err = loadImageArchive(ctx, dockerClient, spec.ImageName, spec.ImageArchive)
+		case ImageSourceRegistry:
+			err = pullImage(ctx, dockerClient, spec.ImageName, platformName(platform))
+		default:
+			err = fmt.Errorf("unknown image source")
+		}
+
+		if err == nil && platform != nil {
+			var localImage types.ImageInspect
+			if localImage, _, err = dockerClient.ImageInspectWithRaw(ctx, spec.ImageName); err == nil {
+				err = checkImagePlatform(localImage, platform)
+			}
+		}
+
+		if err == nil {
+			tflog.Info(ctx, "Resolved local image", map[string]any{
+				"image_name": spec.ImageName,
+				"source":     source,
+			})
+			return
+		}
+
+		tflog.Debug(ctx, "Image source did not provide the image", map[string]any{
+			"image_name": spec.ImageName,
+			"source":     source,
+			"error":      err.Error(),
+		})
+		failures = append(failures, fmt.Sprintf("%s: %v", source, err))
+	}
+
+	errors.AddError(
+		"Image Not Found",
+		fmt.Sprintf("Image %q could not be sourced from any of the configured image sources:\n%s", spec.ImageName, strings.Join(failures, "\n")),
+	)
+	return
+}
+
+func loadImageArchive(ctx context.Context, dockerClient DockerAPI, localImageName, archivePath string) error {
+	if archivePath == "" {
+		return fmt.Errorf("image_archive is not set")
+	}
+
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	loadResp, err := dockerClient.ImageLoad(ctx, archive, true)
+	if err != nil {
+		return err
+	}
+	defer loadResp.Body.Close()
+
+	if _, err = io.Copy(io.Discard, loadResp.Body); err != nil {
+		return err
+	}
+
+	_, _, err = dockerClient.ImageInspectWithRaw(ctx, localImageName)
+	if err != nil {
+		return fmt.Errorf("archive did not contain the image: %w", err)
+	}
+
+	return nil
+}
+
+func pullImage(ctx context.Context, dockerClient DockerAPI, localImageName, platform string) error {
+	pullReader, err := dockerClient.ImagePull(ctx, localImageName, image.PullOptions{Platform: platform})
+	if err != nil {
+		return err
+	}
+	defer pullReader.Close()
+
+	if _, err = io.Copy(io.Discard, pullReader); err != nil {
+		return err
+	}
+
+	_, _, err = dockerClient.ImageInspectWithRaw(ctx, localImageName)
+	return err
+}
+
+// pushImageToRegistry tags the local image with tag in Daytona's registry and
+// pushes it, returning the remote reference and its digest. The digest is taken from
+// the push output, or else from the registry once it serves the image.
+func (s *Service) pushImageToRegistry(ctx context.Context, dockerClient DockerAPI, localImageName, tag string, platform *ocispec.Platform) (pushed PushedImage, warns, errors diag.Diagnostics) {
+	tokenResponse, err := s.API.GetTransientPushAccess(ctx)
+	if err != nil {
+		errors.AddError("API Error", fmt.Sprintf("Unable to get push access token: %v", err))
+		return
+	}
+
+	encodedAuth, err := json.Marshal(registry.AuthConfig{
+		Username:      tokenResponse.Username,
+		Password:      tokenResponse.Secret,
+		ServerAddress: tokenResponse.RegistryUrl,
+	})
+	if err != nil {
+		errors.AddError("Auth Error", fmt.Sprintf("Unable to encode docker auth config: %v", err))
+		return
+	}
+
+	targetImage := remoteImageName(tokenResponse, localImageName, tag)
+
+	err = dockerClient.ImageTag(ctx, localImageName, targetImage)
+	if err != nil {
+		errors.AddError("Tag Error", fmt.Sprintf("Unable to tag image: %v", err))
+		return
+	}
+
+	pushOptions := image.PushOptions{
+		RegistryAuth: base64.URLEncoding.EncodeToString(encodedAuth),
+	}
+	// engines older than API 1.46, like Podman, can't push a platform of an
+	// image, their images have a single one which was checked already
+	if platform != nil {
+		version, err := dockerClient.ServerVersion(ctx)
+		if err != nil || !versions.LessThan(version.APIVersion, pushPlatformAPIVersion) {
+			pushOptions.Platform = platform
+		}
+	}
+
+	pushReader, err := dockerClient.ImagePush(ctx, targetImage, pushOptions)
+	if err != nil {
+		errors.AddError("Push Error", fmt.Sprintf("Unable to push image: %v", err))
+		return
+	}
+	defer pushReader.Close()
+
+	digest, err := readPushOutput(ctx, pushReader)
+	if err != nil {
+		errors.AddError("Push Error", fmt.Sprintf("Error during image push: %v", err))
+		return
+	}
+	if digest != "" {
+		pushed.RemoteImageName = targetImage
+		pushed.Digest = digest
+		return
+	}
+
+	// without a digest in the push output, wait for the registry to serve it
+	ctx, cancel := s.operationContext(ctx)
+	defer cancel()
+	backoff := s.newBackoff()
+
+	for {
+		var distribution registry.DistributionInspect
+
+		select {
+		case <-ctx.Done():
+			errors.AddError("Image Availability Error", fmt.Sprintf("Cancelled during waiting for image to become available: %v", ctx.Err()))
+			return
+		default:
+			distribution, err = dockerClient.DistributionInspect(ctx, targetImage, base64.URLEncoding.EncodeToString(encodedAuth))
+		}
+
+		if err == nil {
+			pushed.RemoteImageName = targetImage
+			pushed.Digest = distribution.Descriptor.Digest.String()
+			break
+		}
+
+		tflog.Info(ctx, "Waiting for the image to become available")
+		backoff.wait(ctx)
+	}
+
+	return
+}
+
+// readPushOutput follows the push output until its end, logging the upload
+// progress periodically. It returns the digest the engine reported for the
+// pushed image, if any. Pushes that fail midway report it in the output.
+func readPushOutput(ctx context.Context, pushReader io.Reader) (digest string, err error) {
+	type layerProgress struct {
+		current, total int64
+		done           bool
+	}
+	layers := map[string]*layerProgress{}
+	lastLogged := time.Now()
+
+	decoder := json.NewDecoder(pushReader)
+	for {
+		var message struct {
+			ID             string `json:"id"`
+			Status         string `json:"status"`
+			ProgressDetail struct {
+				Current int64 `json:"current"`
+				Total   int64 `json:"total"`
+			} `json:"progressDetail"`
+			Error       string `json:"error"`
+			ErrorDetail struct {
+				Message string `json:"message"`
+			} `json:"errorDetail"`
+			Aux *types.PushResult `json:"aux"`
+		}
+		if err := decoder.Decode(&message); errors.Is(err, io.EOF) {
+			return digest, nil
+		} else if err != nil {
+			return "", fmt.Errorf("unable to read push output: %w", err)
+		}
+
+		if message.ErrorDetail.Message != "" {
+			return "", errors.New(message.ErrorDetail.Message)
+		} else if message.Error != "" {
+			return "", errors.New(message.Error)
+		}
+		if message.Aux != nil && message.Aux.Digest != "" {
+			digest = message.Aux.Digest
+		}
+		if message.ID == "" {
+			continue
+		}
+
+		layer, ok := layers[message.ID]
+		if !ok {
+			layer = &layerProgress{}
+			layers[message.ID] = layer
+		}
+		switch {
+		case message.Status == "Pushing":
+			layer.current, layer.total = message.ProgressDetail.Current, message.ProgressDetail.Total
+		case message.Status == "Pushed", message.Status == "Layer already exists", strings.HasPrefix(message.Status, "Mounted from"):
+			layer.done = true
+		}
+
+		if time.Since(lastLogged) < pushProgressInterval {
+			continue
+		}
+		lastLogged = time.Now()
+
+		var done int
+		var current, total int64
+		for _, layer := range layers {
+			if layer.done {
+				done++
+			}
+			current += layer.current
+			total += layer.total
+		}
+		tflog.Info(ctx, "Pushing image", map[string]any{
+			"layers_done":  done,
+			"layers":       len(layers),
+			"bytes_pushed": current,
+			"bytes_total":  total,
+		})
+	}
+}
+
+// remoteImageName names the image in Daytona's registry after the last path
+// component of its repository, with the given tag.
+func remoteImageName(access *apiclient.RegistryPushAccessDto, imageName, tag string) string {
+	repo, _, _ := strings.Cut(imageName, "@")
+	if named, err := reference.ParseNormalizedNamed(repo); err == nil {
+		repo = reference.Path(named)
+	} else if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	repo = repo[strings.LastIndex(repo, "/")+1:]
+
+	return fmt.Sprintf("%s/%s/%s:%s", access.RegistryUrl, access.Project, repo, tag)
+}
+
+// remoteTag expands the placeholders of a remote tag template for the image.
+// digest is the pushed image's digest or ID, empty if it isn't known before
+// the push.
+func remoteTag(template, imageName, digest string) (string, error) {
+	if template == "" {
+		template = "{timestamp}"
+	}
+
+	localTag, shortDigest := remoteTagValues(imageName, digest)
+	if shortDigest == "" && strings.Contains(template, "{digest}") {
+		return "", fmt.Errorf("remote tag %q uses {digest}, which isn't known for image %q before pushing it, copied images have to be pinned by digest", template, imageName)
+	}
+
+	tag := strings.NewReplacer(
+		"{timestamp}", time.Now().Format("20060102150405"),
+		"{tag}", localTag,
+		"{digest}", shortDigest,
+	).Replace(template)
+	if match := reference.TagRegexp.FindString(tag); match != tag {
+		return "", fmt.Errorf("remote tag %q expands to %q, which isn't a valid tag", template, tag)
+	}
+	return tag, nil
+}
+
+// remoteTagPattern matches the tags remoteTag expands the template to for the
+// image, whatever the time of the push.
+func remoteTagPattern(template, imageName, digest string) *regexp.Regexp {
+	if template == "" {
+		template = "{timestamp}"
+	}
+
+	localTag, shortDigest := remoteTagValues(imageName, digest)
+	parts := strings.Split(template, "{timestamp}")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(strings.NewReplacer("{tag}", localTag, "{digest}", shortDigest).Replace(part))
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, "[0-9]{14}") + "$")
+}
+
+// remoteTagValues returns what {tag} and {digest} expand to for the image,
+// the digest being empty if it isn't known.
+func remoteTagValues(imageName, digest string) (localTag, shortDigest string) {
+	localTag = "latest"
+	if named, err := reference.ParseNormalizedNamed(imageName); err == nil {
+		if tagged, ok := named.(reference.Tagged); ok {
+			localTag = tagged.Tag()
+		}
+	}
+
+	_, shortDigest, _ = strings.Cut(digest, ":")
+	if len(shortDigest) < remoteTagDigestLength {
+		return localTag, ""
+	}
+	return localTag, shortDigest[:remoteTagDigestLength]
+}
+
+// copyImageToRegistry copies a remote image into Daytona's registry. The
+// source registry is authenticated with the credentials of the Docker CLI's
+// config, if it has any.
+func (s *Service) copyImageToRegistry(ctx context.Context, imageName, tagTemplate string, platform *ocispec.Platform) (pushed PushedImage, warns, errors diag.Diagnostics) {
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		errors.AddError("Invalid Image Name", fmt.Sprintf("Unable to parse image name %q: %v", imageName, err))
+		return
+	}
+	source, err := name.ParseReference(named.String())
+	if err != nil {
+		errors.AddError("Invalid Image Name", fmt.Sprintf("Unable to parse image name %q: %v", imageName, err))
+		return
+	}
+
+	var sourceDigest string
+	if canonical, ok := named.(reference.Canonical); ok {
+		sourceDigest = canonical.Digest().String()
+	}
+	tag, err := remoteTag(tagTemplate, imageName, sourceDigest)
+	if err != nil {
+		errors.AddError("Invalid Remote Tag", err.Error())
+		return
+	}
+
+	tokenResponse, err := s.API.GetTransientPushAccess(ctx)
+	if err != nil {
+		errors.AddError("API Error", fmt.Sprintf("Unable to get push access token: %v", err))
+		return
+	}
+
+	targetImage := remoteImageName(tokenResponse, imageName, tag)
+	target, err := name.ParseReference(targetImage)
+	if err != nil {
+		errors.AddError("Invalid Image Name", fmt.Sprintf("Unable to parse remote image name %q: %v", targetImage, err))
+		return
+	}
+
+	tflog.Info(ctx, "Copying image into Daytona's registry", map[string]any{
+		"image_name":        imageName,
+		"remote_image_name": targetImage,
+	})
+
+	digest, err := copyImage(ctx, source, target, platform, s.registryOptions(ctx, target, tokenResponse))
+	if err != nil {
+		errors.AddError("Copy Error", fmt.Sprintf("Unable to copy image %q: %v", imageName, err))
+		return
+	}
+
+	pushed.RemoteImageName = targetImage
+	pushed.Digest = digest
+	return
+}
+
+// parsePlatform parses an os/arch[/variant] platform like linux/amd64, or
+// returns nil for an empty one. Architectures are normalized the way Docker
+// does, so x86_64 is amd64 and aarch64 is arm64.
+func parsePlatform(platform string) (*ocispec.Platform, error) {
+	if platform == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(strings.ToLower(platform), "/")
+	if len(parts) < 2 || len(parts) > 3 || slices.Contains(parts, "") {
+		return nil, fmt.Errorf("platform %q must have the form os/arch[/variant], e.g. linux/amd64", platform)
+	}
+
+	parsed := &ocispec.Platform{OS: parts[0], Architecture: parts[1]}
+	switch parsed.Architecture {
+	case "x86_64", "x86-64":
+		parsed.Architecture = "amd64"
+	case "aarch64":
+		parsed.Architecture = "arm64"
+	}
+	if len(parts) == 3 {
+		parsed.Variant = parts[2]
+	}
+	return parsed, nil
+}
+
+// matchesPlatform reports whether an image built for os, arch and variant
+// runs on the platform. A platform without variant matches all of them.
+func matchesPlatform(platform *ocispec.Platform, os, arch, variant string) bool {
+	return platform.OS == os && platform.Architecture == arch &&
+		(platform.Variant == "" || platform.Variant == variant)
+}
+
+// checkImagePlatform fails if the local image isn't built for the platform.
+func checkImagePlatform(localImage types.ImageInspect, platform *ocispec.Platform) error {
+	if platform == nil || matchesPlatform(platform, localImage.Os, localImage.Architecture, localImage.Variant) {
+		return nil
+	}
+	return fmt.Errorf("image is built for %s, not %s", formatPlatform(localImage.Os, localImage.Architecture, localImage.Variant), platformName(platform))
+}
+
+// platformName formats a parsed platform for the Docker daemon.
+func platformName(platform *ocispec.Platform) string {
+	if platform == nil {
+		return ""
+	}
+	return formatPlatform(platform.OS, platform.Architecture, platform.Variant)
+}
+
+func formatPlatform(os, arch, variant string) string {
+	if variant == "" {
+		return os + "/" + arch
+	}
+	return os + "/" + arch + "/" + variant
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPushImageFallsBackToRegistry(t *testing.T) {
+	docker := newFakeDocker()
+	docker.pullable["app:latest"] = true
+	s := newTestService(newFakeAPI(), docker)
+
+	pushed, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:latest",
+		ImageSources: []string{ImageSourceLocal, ImageSourceArchive, ImageSourceRegistry},
+	})
+	requireNoErrors(t, errs)
+
+	if !slices.Contains(docker.calls, "ImagePull app:latest") {
+		t.Errorf("image wasn't pulled, calls: %v", docker.calls)
+	}
+	if pushed.Digest != "sha256:0123456789abcdef" {
+		t.Errorf("unexpected digest %q", pushed.Digest)
+	}
+}
+
+func TestPushImagePrefersEarlierSources(t *testing.T) {
+	docker := newFakeDocker("app:latest")
+	docker.pullable["app:latest"] = true
+	s := newTestService(newFakeAPI(), docker)
+
+	_, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:latest",
+		ImageSources: []string{ImageSourceLocal, ImageSourceRegistry},
+	})
+	requireNoErrors(t, errs)
+
+	if slices.Contains(docker.calls, "ImagePull app:latest") {
+		t.Errorf("image present locally shouldn't be pulled, calls: %v", docker.calls)
+	}
+}
+
+func TestPushImageArchiveWithoutPath(t *testing.T) {
+	s := newTestService(newFakeAPI(), newFakeDocker())
+
+	_, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:latest",
+		ImageSources: []string{ImageSourceArchive},
+	})
+	requireError(t, errs, "image_archive is not set")
+}
+
+func TestPushImagePullsOtherPlatform(t *testing.T) {
+	docker := newFakeDocker("app:latest")
+	docker.platforms["app:latest"] = "linux/arm64"
+	docker.pullable["app:latest"] = true
+	s := newTestService(newFakeAPI(), docker)
+
+	_, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:latest",
+		ImageSources: []string{ImageSourceLocal, ImageSourceRegistry},
+		Platform:     "linux/x86_64",
+	})
+	requireNoErrors(t, errs)
+
+	if docker.platforms["app:latest"] != "linux/amd64" {
+		t.Errorf("image wasn't pulled for the platform, calls: %v", docker.calls)
+	}
+}
+
+func TestPushImagePlatformMismatch(t *testing.T) {
+	docker := newFakeDocker("app:latest")
+	docker.platforms["app:latest"] = "linux/arm64/v8"
+	s := newTestService(newFakeAPI(), docker)
+
+	_, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:latest",
+		ImageSources: []string{ImageSourceLocal},
+		Platform:     "linux/amd64",
+	})
+	requireError(t, errs, "local: image is built for linux/arm64/v8, not linux/amd64")
+
+	_, _, errs = s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:latest",
+		ImageSources: []string{ImageSourceLocal},
+		Platform:     "amd64",
+	})
+	requireError(t, errs, "must have the form os/arch[/variant]")
+}
+
+func TestSameImageReference(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"ubuntu", "ubuntu", true},
+		{"ubuntu", "ubuntu:latest", true},
+		{"ubuntu", "docker.io/library/ubuntu:latest", true},
+		{"gel/server:6", "docker.io/gel/server:6", true},
+		{"ubuntu:22.04", "ubuntu:latest", false},
+		{"ubuntu", "debian", false},
+		{"ghcr.io/gel/server", "gel/server", false},
+		{"NOT A REFERENCE", "not a reference", false},
+		{"", "ubuntu", false},
+	}
+
+	for _, test := range tests {
+		if actual := SameImageReference(test.a, test.b); actual != test.expected {
+			t.Errorf("SameImageReference(%q, %q) = %v, expected %v", test.a, test.b, actual, test.expected)
+		}
+	}
+}
+
+func TestPushImageKeepLocalTag(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		docker := newFakeDocker("app:latest")
+		s := newTestService(newFakeAPI(), docker)
+
+		pushed, _, errs := s.PushImage(context.Background(), ImageSpec{
+			ImageName:    "app:latest",
+			ImageSources: []string{ImageSourceLocal},
+			KeepLocalTag: keep,
+		})
+		requireNoErrors(t, errs)
+
+		if docker.images[pushed.RemoteImageName] != keep {
+			t.Errorf("KeepLocalTag=%v but remote tag present=%v", keep, docker.images[pushed.RemoteImageName])
+		}
+	}
+}
+
+func TestPushImageDigestFromPushOutput(t *testing.T) {
+	docker := newFakeDocker("app:latest")
+	docker.pushOutput = `{"status":"Pushed"}` + "\n" +
+		`{"status":"latest: digest: sha256:fedcba9876543210 size: 528","aux":{"Tag":"latest","Digest":"sha256:fedcba9876543210","Size":528}}` + "\n"
+	s := newTestService(newFakeAPI(), docker)
+
+	pushed, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:latest",
+		ImageSources: []string{ImageSourceLocal},
+	})
+	requireNoErrors(t, errs)
+
+	if pushed.Digest != "sha256:fedcba9876543210" {
+		t.Errorf("unexpected digest %q", pushed.Digest)
+	}
+	if slices.ContainsFunc(docker.calls, func(call string) bool { return strings.HasPrefix(call, "DistributionInspect") }) {
+		t.Errorf("digest from the push output shouldn't be looked up, calls: %v", docker.calls)
+	}
+}
+
+func TestPushImagePlatformWithOlderEngine(t *testing.T) {
+	for _, test := range []struct {
+		apiVersion string
+		expected   string
+	}{
+		{"1.47", " linux/amd64"},
+		// Podman
+		{"1.41", ""},
+	} {
+		docker := newFakeDocker("app:latest")
+		docker.apiVersion = test.apiVersion
+		s := newTestService(newFakeAPI(), docker)
+
+		pushed, _, errs := s.PushImage(context.Background(), ImageSpec{
+			ImageName:    "app:latest",
+			ImageSources: []string{ImageSourceLocal},
+			Platform:     "linux/amd64",
+		})
+		requireNoErrors(t, errs)
+
+		if !slices.Contains(docker.calls, "ImagePush "+pushed.RemoteImageName+test.expected) {
+			t.Errorf("API %s: unexpected push, calls: %v", test.apiVersion, docker.calls)
+		}
+	}
+}
+
+func TestPushImageReportsPushErrors(t *testing.T) {
+	docker := newFakeDocker("app:latest")
+	docker.pushOutput = `{"status":"Preparing","id":"a1b2c3"}` + "\n" +
+		`{"status":"Pushing","id":"a1b2c3","progressDetail":{"current":512,"total":1024}}` + "\n" +
+		`{"errorDetail":{"message":"denied: quota exceeded"},"error":"denied: quota exceeded"}` + "\n"
+	s := newTestService(newFakeAPI(), docker)
+
+	_, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:latest",
+		ImageSources: []string{ImageSourceLocal},
+	})
+	requireError(t, errs, "Error during image push: denied: quota exceeded")
+
+	if slices.ContainsFunc(docker.calls, func(call string) bool { return strings.HasPrefix(call, "DistributionInspect") }) {
+		t.Errorf("failed push shouldn't wait for the image, calls: %v", docker.calls)
+	}
+}
+
+func TestReadPushOutput(t *testing.T) {
+	output := `{"status":"The push refers to repository [registry.example.com/project/app]"}` + "\n" +
+		`{"status":"Preparing","id":"a1b2c3"}` + "\n" +
+		`{"status":"Preparing","id":"d4e5f6"}` + "\n" +
+		`{"status":"Pushing","id":"a1b2c3","progressDetail":{"current":1024,"total":1024}}` + "\n" +
+		`{"status":"Pushed","id":"a1b2c3"}` + "\n" +
+		`{"status":"Layer already exists","id":"d4e5f6"}` + "\n" +
+		`{"progressDetail":{},"aux":{"Tag":"1","Digest":"sha256:fedcba9876543210","Size":528}}` + "\n"
+
+	digest, err := readPushOutput(context.Background(), strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != "sha256:fedcba9876543210" {
+		t.Errorf("unexpected digest %q", digest)
+	}
+
+	if _, err := readPushOutput(context.Background(), strings.NewReader(`{"status":"Pushing"`)); err == nil {
+		t.Error("expected an error for truncated output")
+	}
+}
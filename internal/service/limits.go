@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+// SandboxLimits are the most resources the organization allows a single
+// sandbox, as set by its tier. Zero means no limit.
+type SandboxLimits struct {
+	Cpu    float32
+	Memory float32
+	Disk   float32
+}
+
+// SandboxLimits returns the per-sandbox limits of the organization.
+func (s *Service) SandboxLimits(ctx context.Context) (SandboxLimits, error) {
+	organization, err := s.API.GetOrganization(ctx)
+	if err != nil {
+		return SandboxLimits{}, err
+	}
+
+	return SandboxLimits{
+		Cpu:    organization.MaxCpuPerSandbox,
+		Memory: organization.MaxMemoryPerSandbox,
+		Disk:   organization.MaxDiskPerSandbox,
+	}, nil
+}
+
+// Exceeded describes each of cpu, memory and disk above its limit. Sandboxes
+// created from a snapshot that exceeds them aren't accepted by Daytona.
+func (l SandboxLimits) Exceeded(cpu, memory, disk int32) []string {
+	var exceeded []string
+	for _, resource := range []struct {
+		name  string
+		value int32
+		limit float32
+		unit  string
+	}{
+		{"cpu", cpu, l.Cpu, " cores"},
+		{"memory", memory, l.Memory, " GB"},
+		{"disk", disk, l.Disk, " GB"},
+	} {
+		if resource.limit > 0 && float32(resource.value) > resource.limit {
+			exceeded = append(exceeded, fmt.Sprintf("%s of %d%s exceeds the limit of %g%s per sandbox", resource.name, resource.value, resource.unit, resource.limit, resource.unit))
+		}
+	}
+	return exceeded
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestSandboxLimits(t *testing.T) {
+	api := newFakeAPI()
+	api.organization.MaxDiskPerSandbox = 0
+	s := newTestService(api, nil)
+
+	limits, err := s.SandboxLimits(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		cpu, memory, disk int32
+		expected          []string
+	}{
+		"within limits": {cpu: 4, memory: 8, disk: 10},
+		"cpu": {
+			cpu: 8, memory: 8, disk: 10,
+			expected: []string{"cpu of 8 cores exceeds the limit of 4 cores per sandbox"},
+		},
+		"cpu and memory": {
+			cpu: 5, memory: 16, disk: 10,
+			expected: []string{
+				"cpu of 5 cores exceeds the limit of 4 cores per sandbox",
+				"memory of 16 GB exceeds the limit of 8 GB per sandbox",
+			},
+		},
+		"unlimited disk": {cpu: 1, memory: 1, disk: 1000},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			exceeded := limits.Exceeded(test.cpu, test.memory, test.disk)
+			if !slices.Equal(exceeded, test.expected) {
+				t.Errorf("expected %q, got %q", test.expected, exceeded)
+			}
+		})
+	}
+}
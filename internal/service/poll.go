@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// defaultMaxPollInterval caps the backoff between polls, so finished
+// operations are still noticed soon.
+const defaultMaxPollInterval = 30 * time.Second
+
+// backoff spaces out the polls of a wait loop. The interval starts at the
+// service's PollInterval and doubles after each poll up to its
+// MaxPollInterval. Jitter keeps resources that wait in parallel from polling
+// the API in lockstep.
+type backoff struct {
+	interval    time.Duration
+	maxInterval time.Duration
+}
+
+func (s *Service) newBackoff() *backoff {
+	return &backoff{
+		interval:    s.PollInterval,
+		maxInterval: max(s.PollInterval, s.MaxPollInterval),
+	}
+}
+
+// wait sleeps until the next poll is due, or returns early once ctx is done.
+func (b *backoff) wait(ctx context.Context) {
+	delay := b.next()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// next returns the delay before the next poll, somewhere in the upper half
+// of the current interval, and grows the interval.
+func (b *backoff) next() time.Duration {
+	if b.interval <= 0 {
+		return 0
+	}
+
+	delay := b.interval/2 + rand.N(b.interval/2+1)
+	b.interval = min(2*b.interval, b.maxInterval)
+	return delay
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	s := &Service{PollInterval: time.Second, MaxPollInterval: 5 * time.Second}
+	b := s.newBackoff()
+
+	for _, interval := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		delay := b.next()
+		if delay < interval/2 || delay > interval {
+			t.Errorf("expected a delay between %v and %v, got %v", interval/2, interval, delay)
+		}
+	}
+}
+
+func TestBackoffWithoutMaxInterval(t *testing.T) {
+	s := &Service{PollInterval: time.Millisecond}
+	b := s.newBackoff()
+
+	for range 3 {
+		if delay := b.next(); delay > time.Millisecond {
+			t.Errorf("expected the interval not to grow, got %v", delay)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// PushModeDaemon has the Docker daemon push the image.
+	PushModeDaemon = "daemon"
+	// PushModeDirect uploads the image from a `docker save` archive to
+	// Daytona's registry, several layers at a time. Archives from the archive
+	// image source are read without a Docker daemon.
+	PushModeDirect = "direct"
+)
+
+// defaultPushParallelism is how many layers direct pushes upload at a time
+// unless configured otherwise.
+const defaultPushParallelism = 4
+
+// pushImageArchiveFile pushes the image from an archive on disk, without a
+// Docker daemon.
+func (s *Service) pushImageArchiveFile(ctx context.Context, archivePath, imageName, tagTemplate string, platform *ocispec.Platform) (pushed PushedImage, err error) {
+	if archivePath == "" {
+		return pushed, fmt.Errorf("image_archive is not set")
+	}
+
+	image, err := archiveImage(archivePath, imageName)
+	if err != nil {
+		return pushed, err
+	}
+
+	return s.uploadImage(ctx, image, imageName, tagTemplate, platform)
+}
+
+// archiveImage finds the image in a `docker save` archive, in either the
+// legacy or the OCI layout. An archive holding a single untagged image
+// provides it for any name.
+func archiveImage(archivePath, imageName string) (v1.Image, error) {
+	opener := func() (io.ReadCloser, error) {
+		return os.Open(archivePath)
+	}
+
+	manifest, err := tarball.LoadManifest(opener)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read archive: %w", err)
+	}
+
+	for _, image := range manifest {
+		for _, repoTag := range image.RepoTags {
+			if !SameImageReference(repoTag, imageName) {
+				continue
+			}
+			tag, err := name.NewTag(repoTag)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse tag %q of archive: %w", repoTag, err)
+			}
+			return tarball.Image(opener, &tag)
+		}
+	}
+	if len(manifest) == 1 && len(manifest[0].RepoTags) == 0 {
+		return tarball.Image(opener, nil)
+	}
+	return nil, fmt.Errorf("archive does not contain image %q", imageName)
+}
+
+// pushSavedImage exports the image from the Docker daemon and pushes the
+// export. The export is kept in a temporary file while uploading, as layers
+// are read concurrently.
+func (s *Service) pushSavedImage(ctx context.Context, dockerClient DockerAPI, imageName, tagTemplate string, platform *ocispec.Platform) (pushed PushedImage, err error) {
+	saveReader, err := dockerClient.ImageSave(ctx, []string{imageName})
+	if err != nil {
+		return pushed, fmt.Errorf("unable to export image: %w", err)
+	}
+	defer saveReader.Close()
+
+	temp, err := os.CreateTemp("", "daytona-image-*.tar")
+	if err != nil {
+		return pushed, err
+	}
+	defer os.Remove(temp.Name())
+
+	tflog.Info(ctx, "Exporting image from the Docker daemon", map[string]any{
+		"image_name": imageName,
+	})
+	_, err = io.Copy(temp, saveReader)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return pushed, fmt.Errorf("unable to export image: %w", err)
+	}
+
+	return s.pushImageArchiveFile(ctx, temp.Name(), imageName, tagTemplate, platform)
+}
+
+// uploadImage uploads the image to Daytona's registry, pushParallelism
+// layers at a time. Uncompressed layers are compressed with gzip first.
+func (s *Service) uploadImage(ctx context.Context, image v1.Image, imageName, tagTemplate string, platform *ocispec.Platform) (pushed PushedImage, err error) {
+	if platform != nil {
+		if err := checkConfigPlatform(image, platform); err != nil {
+			return pushed, err
+		}
+	}
+
+	configDigest, err := image.ConfigName()
+	if err != nil {
+		return pushed, fmt.Errorf("unable to read image config: %w", err)
+	}
+	tag, err := remoteTag(tagTemplate, imageName, configDigest.String())
+	if err != nil {
+		return pushed, err
+	}
+
+	tokenResponse, err := s.API.GetTransientPushAccess(ctx)
+	if err != nil {
+		return pushed, fmt.Errorf("unable to get push access token: %w", err)
+	}
+	targetImage := remoteImageName(tokenResponse, imageName, tag)
+	target, err := name.ParseReference(targetImage)
+	if err != nil {
+		return pushed, fmt.Errorf("unable to parse remote image name %q: %w", targetImage, err)
+	}
+
+	tflog.Info(ctx, "Uploading image to Daytona's registry", map[string]any{
+		"image_name":        imageName,
+		"remote_image_name": targetImage,
+		"parallelism":       s.pushParallelism(),
+	})
+
+	options := append(s.registryOptions(ctx, target, tokenResponse), remote.WithJobs(s.pushParallelism()))
+	digest, err := writeImage(ctx, target, image, options)
+	if err != nil {
+		return pushed, err
+	}
+
+	pushed.RemoteImageName = targetImage
+	pushed.Digest = digest
+	return pushed, nil
+}
+
+func (s *Service) pushParallelism() int {
+	if s.PushParallelism > 0 {
+		return s.PushParallelism
+	}
+	return defaultPushParallelism
+}
+
+// pushDirect pushes the image with the direct push mode. With the archive
+// source first, the archive is pushed without a Docker daemon. Otherwise, or
+// if the archive doesn't provide the image, it is sourced into the daemon
+// and exported from there.
+func (s *Service) pushDirect(ctx context.Context, spec ImageSpec, platform *ocispec.Platform) (pushed PushedImage, warns, errs diag.Diagnostics) {
+	sources := spec.ImageSources
+	if spec.Build == nil && len(sources) > 0 && sources[0] == ImageSourceArchive {
+		pushed, err := s.pushImageArchiveFile(ctx, spec.ImageArchive, spec.ImageName, spec.RemoteTag, platform)
+		if err == nil {
+			return pushed, warns, errs
+		}
+		if len(sources) == 1 {
+			errs.AddError("Push Error", fmt.Sprintf("Unable to push image %q from archive: %v", spec.ImageName, err))
+			return pushed, warns, errs
+		}
+		warns.AddWarning("Image Source Skipped", fmt.Sprintf("Unable to push image %q from archive, trying the other image sources: %v", spec.ImageName, err))
+		spec.ImageSources = sources[1:]
+	}
+
+	dockerClient, err := s.NewDocker()
+	if err != nil {
+		errs.AddError("Docker Client Error", fmt.Sprintf("Unable to create Docker client: %v", err))
+		return
+	}
+	defer dockerClient.Close()
+
+	localImage, errors := s.prepareLocalImage(ctx, dockerClient, spec, platform)
+	errs.Append(errors...)
+	if errs.HasError() {
+		return
+	}
+
+	pushed, err = s.pushSavedImage(ctx, dockerClient, spec.ImageName, spec.RemoteTag, platform)
+	if err != nil {
+		errs.AddError("Push Error", fmt.Sprintf("Unable to push image %q: %v", spec.ImageName, err))
+		return
+	}
+	pushed.LocalImageID = localImage.ID
+	return
+}
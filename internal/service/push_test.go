@@ -0,0 +1,316 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+// writeImageArchive writes a `docker save` archive in the legacy layout,
+// holding a single image with the given layers.
+func writeImageArchive(t *testing.T, repoTag, platform string, layers ...[]byte) []byte {
+	t.Helper()
+
+	var diffIDs []string
+	for _, layer := range layers {
+		uncompressed := layer
+		if reader, err := gzip.NewReader(bytes.NewReader(layer)); err == nil {
+			uncompressed, _ = io.ReadAll(reader)
+		}
+		diffIDs = append(diffIDs, digestOf(uncompressed))
+	}
+
+	osName, arch, _ := strings.Cut(platform, "/")
+	config, _ := json.Marshal(map[string]any{
+		"os":           osName,
+		"architecture": arch,
+		"rootfs":       map[string]any{"type": "layers", "diff_ids": diffIDs},
+	})
+	configName := strings.TrimPrefix(digestOf(config), "sha256:") + ".json"
+
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	add := func(header *tar.Header, content []byte) {
+		header.Size = int64(len(content))
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		writer.Write(content)
+	}
+
+	add(&tar.Header{Name: configName, Typeflag: tar.TypeReg, Mode: 0o644}, config)
+	var layerNames []string
+	for i, layer := range layers {
+		name := strings.Repeat(string(rune('a'+i)), 8)
+		add(&tar.Header{Name: name + "/", Typeflag: tar.TypeDir, Mode: 0o755}, nil)
+		add(&tar.Header{Name: name + "/layer.tar", Typeflag: tar.TypeReg, Mode: 0o644}, layer)
+		// newer Docker versions link layers to their OCI blobs
+		add(&tar.Header{Name: name + "/blob", Typeflag: tar.TypeSymlink, Linkname: "layer.tar"}, nil)
+		layerNames = append(layerNames, name+"/blob")
+	}
+
+	var repoTags []string
+	if repoTag != "" {
+		repoTags = []string{repoTag}
+	}
+	content, _ := json.Marshal([]tarball.Descriptor{{Config: configName, RepoTags: repoTags, Layers: layerNames}})
+	add(&tar.Header{Name: "manifest.json", Typeflag: tar.TypeReg, Mode: 0o644}, content)
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func layerTar(t *testing.T, name, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := tar.NewWriter(&buf)
+	writer.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))})
+	writer.Write([]byte(content))
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	writer.Write(content)
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newDirectPushService(t *testing.T, registry *fakeRegistry, docker *fakeDocker) *Service {
+	api := newFakeAPI()
+	api.registryURL = registry.host()
+	s := newTestService(api, docker)
+	if docker == nil {
+		s.NewDocker = func() (DockerAPI, error) {
+			return nil, errors.New("no Docker daemon")
+		}
+	}
+	s.HTTPClient = registry.server.Client()
+	return s
+}
+
+// pushedImage returns the image pushed as the tag of the remote image.
+func pushedImage(t *testing.T, registry *fakeRegistry, pushed PushedImage) v1.Image {
+	t.Helper()
+
+	tag := pushed.RemoteImageName[strings.LastIndex(pushed.RemoteImageName, ":")+1:]
+	described := registry.get(t, "project/app:"+tag)
+	if described == nil {
+		t.Fatalf("image wasn't pushed as %q", tag)
+	}
+	if described.Digest.String() != pushed.Digest {
+		t.Errorf("expected digest %q, got %q", described.Digest, pushed.Digest)
+	}
+
+	image, err := described.Image()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return image
+}
+
+// pushedLayers returns the compressed content of the image's layers.
+func pushedLayers(t *testing.T, image v1.Image) [][]byte {
+	t.Helper()
+
+	layers, err := image.Layers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var contents [][]byte
+	for _, layer := range layers {
+		reader, err := layer.Compressed()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, content)
+	}
+	return contents
+}
+
+func TestPushImageDirectFromArchive(t *testing.T) {
+	binary, config := layerTar(t, "app", "binary"), layerTar(t, "etc/app.conf", "config")
+	archivePath := filepath.Join(t.TempDir(), "app.tar")
+	os.WriteFile(archivePath, writeImageArchive(t, "app:1.0", "linux/amd64", binary, config), 0o644)
+
+	for _, parallelism := range []int{1, 4} {
+		registry := newFakeRegistry(t)
+		s := newDirectPushService(t, registry, nil)
+		s.PushParallelism = parallelism
+
+		pushed, _, errs := s.PushImage(context.Background(), ImageSpec{
+			ImageName:    "app:1.0",
+			ImageSources: []string{ImageSourceArchive},
+			ImageArchive: archivePath,
+			Platform:     "linux/amd64",
+			RemoteTag:    "{tag}-{digest}",
+			PushMode:     PushModeDirect,
+		})
+		requireNoErrors(t, errs)
+
+		image := pushedImage(t, registry, pushed)
+		configDigest, err := image.ConfigName()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(pushed.RemoteImageName, ":1.0-"+configDigest.Hex[:12]) {
+			t.Errorf("expected the tag to use the config digest, got %q", pushed.RemoteImageName)
+		}
+		if pushed.LocalImageID != "" {
+			t.Errorf("expected no local image ID, got %q", pushed.LocalImageID)
+		}
+
+		layers := pushedLayers(t, image)
+		if len(layers) != 2 {
+			t.Fatalf("expected 2 layers, got %d", len(layers))
+		}
+		for i, expected := range [][]byte{binary, config} {
+			reader, err := gzip.NewReader(bytes.NewReader(layers[i]))
+			if err != nil {
+				t.Fatalf("layer %d wasn't uploaded gzipped: %v", i, err)
+			}
+			if content, _ := io.ReadAll(reader); !bytes.Equal(content, expected) {
+				t.Errorf("layer %d doesn't hold the archive's layer", i)
+			}
+		}
+	}
+}
+
+func TestPushImageDirectFromCompressedArchive(t *testing.T) {
+	compressed := gzipped(t, layerTar(t, "app", "binary"))
+	archivePath := filepath.Join(t.TempDir(), "app.tar")
+	os.WriteFile(archivePath, writeImageArchive(t, "app:1.0", "linux/amd64", compressed), 0o644)
+
+	registry := newFakeRegistry(t)
+	s := newDirectPushService(t, registry, nil)
+
+	pushed, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:1.0",
+		ImageSources: []string{ImageSourceArchive},
+		ImageArchive: archivePath,
+		PushMode:     PushModeDirect,
+	})
+	requireNoErrors(t, errs)
+
+	if layers := pushedLayers(t, pushedImage(t, registry, pushed)); len(layers) != 1 || digestOf(layers[0]) != digestOf(compressed) {
+		t.Error("compressed layer wasn't uploaded as it is")
+	}
+}
+
+func TestPushImageDirectFromDaemon(t *testing.T) {
+	registry := newFakeRegistry(t)
+	docker := newFakeDocker("app:1.0")
+	docker.savedArchive = writeImageArchive(t, "app:1.0", "linux/amd64", layerTar(t, "app", "binary"))
+	s := newDirectPushService(t, registry, docker)
+
+	pushed, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:1.0",
+		ImageSources: []string{ImageSourceLocal},
+		PushMode:     PushModeDirect,
+	})
+	requireNoErrors(t, errs)
+
+	if layers := pushedLayers(t, pushedImage(t, registry, pushed)); len(layers) != 1 {
+		t.Errorf("expected 1 layer, got %d", len(layers))
+	}
+	if pushed.LocalImageID != "app:1.0" {
+		t.Errorf("expected local image ID %q, got %q", "app:1.0", pushed.LocalImageID)
+	}
+	if !slices.Contains(docker.calls, "ImageSave app:1.0") {
+		t.Errorf("image wasn't exported, calls: %v", docker.calls)
+	}
+	for _, call := range docker.calls {
+		if strings.HasPrefix(call, "ImagePush") || strings.HasPrefix(call, "ImageTag") {
+			t.Errorf("unexpected call %q", call)
+		}
+	}
+}
+
+func TestPushImageDirectFallsBackToDaemon(t *testing.T) {
+	registry := newFakeRegistry(t)
+	docker := newFakeDocker("app:1.0")
+	docker.savedArchive = writeImageArchive(t, "app:1.0", "linux/amd64", layerTar(t, "app", "binary"))
+	archivePath := filepath.Join(t.TempDir(), "other.tar")
+	os.WriteFile(archivePath, writeImageArchive(t, "other:1.0", "linux/amd64", layerTar(t, "other", "binary")), 0o644)
+	s := newDirectPushService(t, registry, docker)
+
+	pushed, warns, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:1.0",
+		ImageSources: []string{ImageSourceArchive, ImageSourceLocal},
+		ImageArchive: archivePath,
+		PushMode:     PushModeDirect,
+	})
+	requireNoErrors(t, errs)
+
+	if warns.WarningsCount() != 1 || !strings.Contains(warns[0].Detail(), `does not contain image "app:1.0"`) {
+		t.Errorf("expected a warning about the archive, got %v", warns)
+	}
+	if pushed.LocalImageID != "app:1.0" {
+		t.Errorf("expected the image of the daemon to be pushed, got %q", pushed.LocalImageID)
+	}
+}
+
+func TestPushImageDirectFailures(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "app.tar")
+	os.WriteFile(archivePath, writeImageArchive(t, "", "linux/arm64", layerTar(t, "app", "binary")), 0o644)
+
+	tests := map[string]struct {
+		spec     ImageSpec
+		expected string
+	}{
+		"platform mismatch": {
+			spec:     ImageSpec{ImageArchive: archivePath, Platform: "linux/amd64"},
+			expected: "image is built for linux/arm64, not linux/amd64",
+		},
+		"missing archive": {
+			spec:     ImageSpec{ImageArchive: filepath.Join(t.TempDir(), "missing.tar")},
+			expected: "no such file or directory",
+		},
+		"not an image archive": {
+			spec:     ImageSpec{ImageArchive: "push_test.go"},
+			expected: "unable to read archive",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			registry := newFakeRegistry(t)
+			s := newDirectPushService(t, registry, nil)
+
+			test.spec.ImageName = "app:1.0"
+			test.spec.ImageSources = []string{ImageSourceArchive}
+			test.spec.PushMode = PushModeDirect
+			_, _, errs := s.PushImage(context.Background(), test.spec)
+			requireError(t, errs, test.expected)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/distribution/reference"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// pushAccessKeychain authenticates with Daytona's registry using the push
+// access, leaving other registries to the next keychain.
+type pushAccessKeychain struct {
+	registry string
+	access   *apiclient.RegistryPushAccessDto
+}
+
+func (k pushAccessKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	if resource.RegistryStr() != k.registry {
+		return authn.Anonymous, nil
+	}
+	return &authn.Basic{Username: k.access.Username, Password: k.access.Secret}, nil
+}
+
+// registryOptions configures requests to registries. Daytona's registry is
+// authenticated with the push access, others the way the Docker CLI does,
+// from the auths and credential helpers of its config file.
+func (s *Service) registryOptions(ctx context.Context, target name.Reference, access *apiclient.RegistryPushAccessDto) []remote.Option {
+	transport := s.httpClient().Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithTransport(transport),
+		remote.WithAuthFromKeychain(authn.NewMultiKeychain(
+			pushAccessKeychain{registry: target.Context().RegistryStr(), access: access},
+			authn.DefaultKeychain,
+		)),
+	}
+}
+
+// maxTimestampedCandidates is how many of the newest tags with a timestamp
+// findPushedImage checks, so long-lived repositories don't cost a request
+// per tag.
+const maxTimestampedCandidates = 5
+
+// findPushedImage looks in Daytona's registry for the image an earlier, failed
+// attempt to create the snapshot pushed. The candidates are the image of the
+// leftover snapshot, if the attempt registered one, and the name the push
+// would use or, if the remote tag has a timestamp, the newest tags of the
+// repository it could have expanded to. A candidate is only taken if it's the
+// same image, so changed images are pushed again. Built images aren't known
+// before building them.
+func (s *Service) findPushedImage(ctx context.Context, spec ImageSpec, leftoverImage string) (pushed PushedImage, found bool) {
+	if spec.Build != nil {
+		return
+	}
+	digest, localImageID := s.imageDigest(ctx, spec)
+	if digest == "" {
+		return
+	}
+
+	tokenResponse, err := s.API.GetTransientPushAccess(ctx)
+	if err != nil {
+		return
+	}
+
+	var candidates []string
+	if strings.HasPrefix(leftoverImage, tokenResponse.RegistryUrl+"/") {
+		candidates = append(candidates, leftoverImage)
+	}
+	if spec.RemoteTag != "" && !strings.Contains(spec.RemoteTag, "{timestamp}") {
+		if tag, err := remoteTag(spec.RemoteTag, spec.ImageName, digest); err == nil {
+			candidates = append(candidates, remoteImageName(tokenResponse, spec.ImageName, tag))
+		}
+	} else {
+		candidates = append(candidates, s.timestampedImages(ctx, tokenResponse, spec, digest)...)
+	}
+
+	for _, candidate := range candidates {
+		target, err := name.ParseReference(candidate)
+		if err != nil {
+			continue
+		}
+		described, err := remote.Get(target, s.registryOptions(ctx, target, tokenResponse)...)
+		if err != nil || !describesImage(described, digest) {
+			continue
+		}
+
+		tflog.Info(ctx, "Found the image pushed by an earlier attempt, registering it without pushing again", map[string]any{
+			"image_name":        spec.ImageName,
+			"remote_image_name": candidate,
+		})
+		pushed.RemoteImageName = candidate
+		pushed.Digest = described.Digest.String()
+		pushed.LocalImageID = localImageID
+		return pushed, true
+	}
+	return
+}
+
+// imageDigest returns the digest the image would be pushed with: the pinned
+// digest of copied images, the config digest of archives pushed directly, and
+// otherwise the ID of the local image. It's empty if it isn't known without
+// sourcing the image.
+func (s *Service) imageDigest(ctx context.Context, spec ImageSpec) (digest, localImageID string) {
+	if slices.Contains(spec.ImageSources, ImageSourceCopy) {
+		if named, err := reference.ParseNormalizedNamed(spec.ImageName); err == nil {
+			if canonical, ok := named.(reference.Canonical); ok {
+				return canonical.Digest().String(), ""
+			}
+		}
+		return "", ""
+	}
+
+	if spec.PushMode == PushModeDirect && len(spec.ImageSources) > 0 && spec.ImageSources[0] == ImageSourceArchive {
+		if image, err := archiveImage(spec.ImageArchive, spec.ImageName); err == nil {
+			if configDigest, err := image.ConfigName(); err == nil {
+				return configDigest.String(), ""
+			}
+		}
+	}
+
+	localImageID, err := s.LocalImageID(ctx, spec.ImageName)
+	if err != nil {
+		return "", ""
+	}
+	return localImageID, localImageID
+}
+
+// timestampedImages lists the newest images in the repository the spec's image
+// is pushed to whose tags the remote tag template could have expanded to.
+func (s *Service) timestampedImages(ctx context.Context, access *apiclient.RegistryPushAccessDto, spec ImageSpec, digest string) (images []string) {
+	target, err := name.ParseReference(remoteImageName(access, spec.ImageName, "latest"))
+	if err != nil {
+		return
+	}
+	tags, err := remote.List(target.Context(), s.registryOptions(ctx, target, access)...)
+	if err != nil {
+		return
+	}
+
+	pattern := remoteTagPattern(spec.RemoteTag, spec.ImageName, digest)
+	tags = slices.DeleteFunc(tags, func(tag string) bool {
+		return !pattern.MatchString(tag)
+	})
+	// the timestamps have a fixed width, so the newest tags sort last
+	slices.Sort(tags)
+	slices.Reverse(tags)
+
+	for _, tag := range tags[:min(len(tags), maxTimestampedCandidates)] {
+		images = append(images, target.Context().Tag(tag).String())
+	}
+	return
+}
+
+// describesImage reports whether the manifest is the image with the digest,
+// or the image whose config has it.
+func describesImage(described *remote.Descriptor, digest string) bool {
+	if described.Digest.String() == digest {
+		return true
+	}
+	if !described.MediaType.IsImage() {
+		return false
+	}
+	image, err := described.Image()
+	if err != nil {
+		return false
+	}
+	configDigest, err := image.ConfigName()
+	return err == nil && configDigest.String() == digest
+}
+
+// copyImage copies an image with its blobs between registries and returns
+// the digest of its manifest. All platforms of multi-platform images are
+// copied, unless platform selects one of them.
+func copyImage(ctx context.Context, source, target name.Reference, platform *ocispec.Platform, options []remote.Option) (string, error) {
+	described, err := remote.Get(source, options...)
+	if err != nil {
+		return "", err
+	}
+
+	if described.MediaType.IsIndex() {
+		index, err := described.ImageIndex()
+		if err != nil {
+			return "", err
+		}
+
+		if platform == nil {
+			if err := remote.WriteIndex(target, index, options...); err != nil {
+				return "", err
+			}
+			return described.Digest.String(), nil
+		}
+
+		indexManifest, err := index.IndexManifest()
+		if err != nil {
+			return "", err
+		}
+		for _, child := range indexManifest.Manifests {
+			if child.Platform != nil && matchesPlatform(platform, child.Platform.OS, child.Platform.Architecture, child.Platform.Variant) {
+				image, err := index.Image(child.Digest)
+				if err != nil {
+					return "", err
+				}
+				return writeImage(ctx, target, image, options)
+			}
+		}
+		return "", fmt.Errorf("%s has no manifest for %s", source, platformName(platform))
+	}
+
+	image, err := described.Image()
+	if err != nil {
+		return "", err
+	}
+	if platform != nil {
+		if err := checkConfigPlatform(image, platform); err != nil {
+			return "", fmt.Errorf("%s: %w", source, err)
+		}
+	}
+	return writeImage(ctx, target, image, options)
+}
+
+// writeImage pushes the image and returns the digest of its manifest.
+func writeImage(ctx context.Context, target name.Reference, image v1.Image, options []remote.Option) (string, error) {
+	updates, wait := logUploadProgress(ctx, target)
+	err := remote.Write(target, image, append(options, remote.WithProgress(updates))...)
+	wait()
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := image.Digest()
+	if err != nil {
+		return "", err
+	}
+	return digest.String(), nil
+}
+
+// logUploadProgress logs the progress of an upload every tenth of its size.
+// The returned function waits for the upload to report its end.
+func logUploadProgress(ctx context.Context, target name.Reference) (chan<- v1.Update, func()) {
+	updates := make(chan v1.Update)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		var logged int64
+		for update := range updates {
+			if update.Total == 0 {
+				continue
+			}
+			if tenths := update.Complete * 10 / update.Total; tenths > logged {
+				logged = tenths
+				tflog.Info(ctx, "Uploading image", map[string]any{
+					"remote_image_name": target.String(),
+					"uploaded_bytes":    update.Complete,
+					"total_bytes":       update.Total,
+				})
+			}
+		}
+	}()
+
+	return updates, func() { <-done }
+}
+
+// checkConfigPlatform fails if the image config isn't for the platform.
+func checkConfigPlatform(image v1.Image, platform *ocispec.Platform) error {
+	config, err := image.ConfigFile()
+	if err != nil {
+		return fmt.Errorf("unable to read image config: %w", err)
+	}
+	if !matchesPlatform(platform, config.OS, config.Architecture, config.Variant) {
+		return fmt.Errorf("image is built for %s, not %s", formatPlatform(config.OS, config.Architecture, config.Variant), platformName(platform))
+	}
+	return nil
+}
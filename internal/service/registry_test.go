@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// fakeRegistry is an in-memory registry behind token authentication. Tokens
+// with push access are only issued for the username and password, as are
+// all tokens of private registries.
+type fakeRegistry struct {
+	server *httptest.Server
+
+	mu                 sync.Mutex
+	username, password string
+	private            bool
+}
+
+func (r *fakeRegistry) setCredentials(username, password string, private bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.username, r.password, r.private = username, password, private
+}
+
+// authorized reports whether a token for the scope is issued to the request.
+func (r *fakeRegistry) authorized(req *http.Request) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, pass, ok := req.BasicAuth()
+	authenticated := ok && user == r.username && pass == r.password
+	return authenticated || !r.private && !strings.Contains(req.URL.Query().Get("scope"), "push")
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	r := &fakeRegistry{username: "user", password: "secret"}
+	handler := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			if !r.authorized(req) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"token": "token"})
+			return
+		}
+
+		if req.Header.Get("Authorization") != "Bearer token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, r.server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *fakeRegistry) host() string {
+	return strings.TrimPrefix(r.server.URL, "http://")
+}
+
+func (r *fakeRegistry) reference(t *testing.T, repository string) name.Reference {
+	t.Helper()
+
+	ref, err := name.ParseReference(r.host() + "/" + repository)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
+
+func (r *fakeRegistry) options() []remote.Option {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return []remote.Option{remote.WithAuth(&authn.Basic{Username: r.username, Password: r.password})}
+}
+
+// push stores an image or index in the registry.
+func (r *fakeRegistry) push(t *testing.T, repository string, taggable remote.Taggable) {
+	t.Helper()
+
+	if err := remote.Push(r.reference(t, repository), taggable, r.options()...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// get returns the descriptor of a manifest in the registry, or nil if it
+// doesn't have it.
+func (r *fakeRegistry) get(t *testing.T, repository string) *remote.Descriptor {
+	t.Helper()
+
+	described, err := remote.Get(r.reference(t, repository), r.options()...)
+	if err != nil {
+		return nil
+	}
+	return described
+}
+
+// testImage returns an image with a random layer, built for the platform.
+func testImage(t *testing.T, platform v1.Platform) v1.Image {
+	t.Helper()
+
+	image, err := random.Image(256, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := image.ConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.OS, config.Architecture, config.Variant = platform.OS, platform.Architecture, platform.Variant
+	image, err = mutate.ConfigFile(image, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return image
+}
+
+// testIndex returns an index of the images, listed for the platform of their
+// config.
+func testIndex(t *testing.T, images ...v1.Image) v1.ImageIndex {
+	t.Helper()
+
+	var addenda []mutate.IndexAddendum
+	for _, image := range images {
+		config, err := image.ConfigFile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		addenda = append(addenda, mutate.IndexAddendum{Add: image, Descriptor: v1.Descriptor{Platform: config.Platform()}})
+	}
+	return mutate.AppendManifests(empty.Index, addenda...)
+}
+
+func mustDigest(t *testing.T, withDigest interface{ Digest() (v1.Hash, error) }) v1.Hash {
+	t.Helper()
+
+	digest, err := withDigest.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return digest
+}
+
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newCopyService(registry *fakeRegistry) *Service {
+	api := newFakeAPI()
+	api.registryURL = registry.host()
+	s := newTestService(api, nil)
+	s.NewDocker = func() (DockerAPI, error) {
+		return nil, errors.New("no Docker daemon")
+	}
+	s.HTTPClient = registry.server.Client()
+	return s
+}
+
+var (
+	linuxAMD64 = v1.Platform{OS: "linux", Architecture: "amd64"}
+	linuxARM64 = v1.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}
+)
+
+func TestPushImageCopiesRemoteImage(t *testing.T) {
+	registry := newFakeRegistry(t)
+	amd64, arm64 := testImage(t, linuxAMD64), testImage(t, linuxARM64)
+	index := testIndex(t, amd64, arm64)
+	registry.push(t, "source/app:1.0", index)
+	s := newCopyService(registry)
+
+	pushed, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    registry.host() + "/source/app:1.0",
+		ImageSources: []string{ImageSourceCopy},
+	})
+	requireNoErrors(t, errs)
+
+	if !strings.HasPrefix(pushed.RemoteImageName, registry.host()+"/project/app:") {
+		t.Errorf("unexpected remote image name %q", pushed.RemoteImageName)
+	}
+	if pushed.Digest != mustDigest(t, index).String() {
+		t.Errorf("expected digest %q, got %q", mustDigest(t, index), pushed.Digest)
+	}
+
+	tag := pushed.RemoteImageName[strings.LastIndex(pushed.RemoteImageName, ":")+1:]
+	if copied := registry.get(t, "project/app:"+tag); copied == nil || copied.Digest.String() != pushed.Digest {
+		t.Errorf("index wasn't pushed as %q", tag)
+	}
+	for _, image := range []v1.Image{amd64, arm64} {
+		if registry.get(t, "project/app@"+mustDigest(t, image).String()) == nil {
+			t.Errorf("platform manifest %s wasn't pushed", mustDigest(t, image))
+		}
+	}
+}
+
+func TestPushImageCopiesPlatform(t *testing.T) {
+	registry := newFakeRegistry(t)
+	amd64, arm64 := testImage(t, linuxAMD64), testImage(t, linuxARM64)
+	registry.push(t, "source/app:1.0", testIndex(t, amd64, arm64))
+	registry.push(t, "source/app:arm64", arm64)
+	s := newCopyService(registry)
+
+	pushed, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    registry.host() + "/source/app:1.0",
+		ImageSources: []string{ImageSourceCopy},
+		Platform:     "linux/arm64",
+	})
+	requireNoErrors(t, errs)
+
+	if pushed.Digest != mustDigest(t, arm64).String() {
+		t.Errorf("expected the arm64 manifest %q, got %q", mustDigest(t, arm64), pushed.Digest)
+	}
+	if registry.get(t, "project/app@"+mustDigest(t, amd64).String()) != nil {
+		t.Error("expected only the arm64 manifest to be pushed")
+	}
+
+	_, _, errs = s.PushImage(context.Background(), ImageSpec{
+		ImageName:    registry.host() + "/source/app:arm64",
+		ImageSources: []string{ImageSourceCopy},
+		Platform:     "linux/amd64",
+	})
+	requireError(t, errs, "is built for linux/arm64/v8, not linux/amd64")
+
+	_, _, errs = s.PushImage(context.Background(), ImageSpec{
+		ImageName:    registry.host() + "/source/app:1.0",
+		ImageSources: []string{ImageSourceCopy},
+		Platform:     "linux/s390x",
+	})
+	requireError(t, errs, "has no manifest for linux/s390x")
+}
+
+func TestPushImageCopiesWithDockerCredentials(t *testing.T) {
+	source := newFakeRegistry(t)
+	source.setCredentials("reader", "password", true)
+	source.push(t, "org/app:1.0", testImage(t, linuxAMD64))
+	target := newFakeRegistry(t)
+	s := newCopyService(target)
+
+	spec := ImageSpec{
+		ImageName:    source.host() + "/org/app:1.0",
+		ImageSources: []string{ImageSourceCopy},
+	}
+
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	_, _, errs := s.PushImage(context.Background(), spec)
+	requireError(t, errs, "401 Unauthorized")
+
+	// credentials of the Docker CLI's config are used for the source, but
+	// not for Daytona's registry
+	configDir := t.TempDir()
+	config, _ := json.Marshal(map[string]any{
+		"auths": map[string]any{
+			source.host(): map[string]string{"auth": base64.StdEncoding.EncodeToString([]byte("reader:password"))},
+			target.host(): map[string]string{"auth": base64.StdEncoding.EncodeToString([]byte("other:password"))},
+		},
+	})
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), config, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", configDir)
+
+	pushed, _, errs := s.PushImage(context.Background(), spec)
+	requireNoErrors(t, errs)
+
+	tag := pushed.RemoteImageName[strings.LastIndex(pushed.RemoteImageName, ":")+1:]
+	if target.get(t, "project/app:"+tag) == nil {
+		t.Errorf("image wasn't pushed as %q", tag)
+	}
+}
+
+func TestPushImageCopyFailures(t *testing.T) {
+	registry := newFakeRegistry(t)
+	registry.push(t, "source/app:1.0", testImage(t, linuxAMD64))
+	s := newCopyService(registry)
+
+	_, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    registry.host() + "/source/app:2.0",
+		ImageSources: []string{ImageSourceCopy},
+	})
+	requireError(t, errs, "MANIFEST_UNKNOWN")
+
+	_, _, errs = s.PushImage(context.Background(), ImageSpec{
+		ImageName:    registry.host() + "/source/app:1.0",
+		ImageSources: []string{ImageSourceCopy, ImageSourceLocal},
+	})
+	requireError(t, errs, "can't be combined with other image sources")
+
+	// the push access doesn't match the credentials anymore
+	registry.setCredentials("user", "rotated", false)
+	_, _, errs = s.PushImage(context.Background(), ImageSpec{
+		ImageName:    registry.host() + "/source/app:1.0",
+		ImageSources: []string{ImageSourceCopy},
+	})
+	requireError(t, errs, "401 Unauthorized")
+}
+
+func TestRemoteImageName(t *testing.T) {
+	api := newFakeAPI()
+	access, _ := api.GetTransientPushAccess(context.Background())
+
+	for _, imageName := range []string{"app", "app:1.0", "ghcr.io/org/app:1.0", "localhost:5000/app", "org/app@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"} {
+		remote := remoteImageName(access, imageName, "1")
+		if remote != "registry.example.com/project/app:1" {
+			t.Errorf("unexpected remote image name %q for %q", remote, imageName)
+		}
+	}
+}
+
+func TestRemoteTag(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		template, imageName, digest string
+		expected                    string
+		err                         string
+	}{
+		{template: "{tag}", imageName: "ghcr.io/org/app:1.0", expected: "1.0"},
+		{template: "{tag}", imageName: "app", expected: "latest"},
+		{template: "{tag}-{digest}", imageName: "app:1.0", digest: digest, expected: "1.0-0123456789ab"},
+		{template: "release-4f2a9c1", imageName: "app:1.0", expected: "release-4f2a9c1"},
+		{template: "{digest}", imageName: "app:1.0", err: "isn't known for image"},
+		{template: "{tag}/x", imageName: "app:1.0", err: "isn't a valid tag"},
+	}
+
+	for _, test := range tests {
+		tag, err := remoteTag(test.template, test.imageName, test.digest)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("remoteTag(%q, %q): expected an error containing %q, got %v", test.template, test.imageName, test.err, err)
+			}
+			continue
+		}
+		if err != nil || tag != test.expected {
+			t.Errorf("remoteTag(%q, %q) = %q, %v, expected %q", test.template, test.imageName, tag, err, test.expected)
+		}
+	}
+
+	tag, err := remoteTag("", "app", "")
+	if err != nil || len(tag) != len("20060102150405") {
+		t.Errorf("unexpected default tag %q, %v", tag, err)
+	}
+}
+
+func TestRemoteTagPattern(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		template, tag string
+		matches       bool
+	}{
+		{template: "", tag: "20240102150405", matches: true},
+		{template: "", tag: "latest"},
+		{template: "{tag}-{timestamp}", tag: "1.0-20240102150405", matches: true},
+		{template: "{tag}-{timestamp}", tag: "1.1-20240102150405"},
+		{template: "{timestamp}.{digest}", tag: "20240102150405.0123456789ab", matches: true},
+		// the dot is literal
+		{template: "{timestamp}.{digest}", tag: "20240102150405x0123456789ab"},
+	}
+
+	for _, test := range tests {
+		if matches := remoteTagPattern(test.template, "app:1.0", digest).MatchString(test.tag); matches != test.matches {
+			t.Errorf("expected the pattern of %q to match %q: %t", test.template, test.tag, test.matches)
+		}
+	}
+}
+
+func TestPushImageRemoteTag(t *testing.T) {
+	docker := newFakeDocker("app:1.0")
+	s := newTestService(newFakeAPI(), docker)
+
+	pushed, _, errs := s.PushImage(context.Background(), ImageSpec{
+		ImageName:    "app:1.0",
+		ImageSources: []string{ImageSourceLocal},
+		RemoteTag:    "{tag}",
+	})
+	requireNoErrors(t, errs)
+
+	if pushed.RemoteImageName != "registry.example.com/project/app:1.0" {
+		t.Errorf("unexpected remote image name %q", pushed.RemoteImageName)
+	}
+}
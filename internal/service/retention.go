@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// RetentionPolicy selects stale snapshots among those whose name starts with
+// NamePrefix. A snapshot expires when it is older than MaxAge or not among
+// the MaxCount newest ones, unless one of the keep rules protects it.
+type RetentionPolicy struct {
+	NamePrefix string
+	// MaxAge of zero doesn't expire snapshots by age
+	MaxAge time.Duration
+	// MaxCount of nil doesn't limit the number of snapshots
+	MaxCount *int32
+	// KeepNames are never expired
+	KeepNames []string
+	// KeepUsedWithin protects snapshots a sandbox was started from recently
+	KeepUsedWithin time.Duration
+}
+
+// Expired returns the snapshots the policy would delete, newest first.
+// General snapshots and snapshots still being processed are never selected.
+func (p RetentionPolicy) Expired(snapshots []apiclient.SnapshotDto, now time.Time) (expired []apiclient.SnapshotDto) {
+	var matching []apiclient.SnapshotDto
+	for _, snapshot := range snapshots {
+		if !snapshot.General && strings.HasPrefix(snapshot.Name, p.NamePrefix) {
+			matching = append(matching, snapshot)
+		}
+	}
+
+	slices.SortStableFunc(matching, func(a, b apiclient.SnapshotDto) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	for i, snapshot := range matching {
+		tooMany := p.MaxCount != nil && i >= int(*p.MaxCount)
+		tooOld := p.MaxAge > 0 && now.Sub(snapshot.CreatedAt) > p.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+
+		if slices.Contains(p.KeepNames, snapshot.Name) || !settledSnapshotState(snapshot.State) {
+			continue
+		}
+
+		lastUsedAt := snapshot.LastUsedAt.Get()
+		if p.KeepUsedWithin > 0 && lastUsedAt != nil && now.Sub(*lastUsedAt) <= p.KeepUsedWithin {
+			continue
+		}
+
+		expired = append(expired, snapshot)
+	}
+
+	return
+}
+
+// settledSnapshotState reports whether a snapshot is done being processed,
+// so deleting it doesn't race with a creation in progress.
+func settledSnapshotState(state apiclient.SnapshotState) bool {
+	switch state {
+	case apiclient.SNAPSHOTSTATE_ACTIVE, apiclient.SNAPSHOTSTATE_INACTIVE, apiclient.SNAPSHOTSTATE_ERROR, apiclient.SNAPSHOTSTATE_BUILD_FAILED:
+		return true
+	}
+	return false
+}
+
+// ExpiredSnapshots lists the organization's snapshots and returns the ones
+// the policy would delete.
+func (s *Service) ExpiredSnapshots(ctx context.Context, policy RetentionPolicy) (expired []apiclient.SnapshotDto, errs diag.Diagnostics) {
+	snapshots, err := s.API.ListSnapshots(ctx)
+	if err != nil {
+		errs.AddError("Client Error", fmt.Sprintf("Unable to list snapshots: %v", err))
+		return
+	}
+
+	return policy.Expired(snapshots, time.Now()), errs
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+)
+
+func TestRetentionPolicyExpired(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	snapshot := func(name string, age time.Duration, state apiclient.SnapshotState) apiclient.SnapshotDto {
+		return apiclient.SnapshotDto{
+			Id:        "id-" + name,
+			Name:      name,
+			State:     state,
+			CreatedAt: now.Add(-age),
+		}
+	}
+
+	recentlyUsed := snapshot("ci-used", 50*24*time.Hour, apiclient.SNAPSHOTSTATE_ACTIVE)
+	recentlyUsed.LastUsedAt = *apiclient.NewNullableTime(apiclient.PtrTime(now.Add(-time.Hour)))
+
+	general := snapshot("ci-general", 60*24*time.Hour, apiclient.SNAPSHOTSTATE_ACTIVE)
+	general.General = true
+
+	snapshots := []apiclient.SnapshotDto{
+		snapshot("ci-1", 1*24*time.Hour, apiclient.SNAPSHOTSTATE_ACTIVE),
+		snapshot("ci-2", 2*24*time.Hour, apiclient.SNAPSHOTSTATE_ACTIVE),
+		snapshot("ci-3", 10*24*time.Hour, apiclient.SNAPSHOTSTATE_ERROR),
+		snapshot("ci-building", 40*24*time.Hour, apiclient.SNAPSHOTSTATE_BUILDING),
+		snapshot("ci-pinned", 45*24*time.Hour, apiclient.SNAPSHOTSTATE_ACTIVE),
+		recentlyUsed,
+		general,
+		snapshot("prod-old", 90*24*time.Hour, apiclient.SNAPSHOTSTATE_ACTIVE),
+	}
+
+	tests := map[string]struct {
+		policy   RetentionPolicy
+		expected []string
+	}{
+		"no limits": {
+			policy: RetentionPolicy{NamePrefix: "ci-"},
+		},
+		"max age": {
+			policy:   RetentionPolicy{NamePrefix: "ci-", MaxAge: 5 * 24 * time.Hour},
+			expected: []string{"ci-3", "ci-pinned", "ci-used"},
+		},
+		"max count": {
+			policy:   RetentionPolicy{NamePrefix: "ci-", MaxCount: int32Pointer(2)},
+			expected: []string{"ci-3", "ci-pinned", "ci-used"},
+		},
+		"keep rules": {
+			policy: RetentionPolicy{
+				NamePrefix:     "ci-",
+				MaxAge:         5 * 24 * time.Hour,
+				KeepNames:      []string{"ci-pinned"},
+				KeepUsedWithin: 24 * time.Hour,
+			},
+			expected: []string{"ci-3"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var actual []string
+			for _, snapshot := range test.policy.Expired(snapshots, now) {
+				actual = append(actual, snapshot.Name)
+			}
+
+			if !slices.Equal(actual, test.expected) {
+				t.Errorf("expected %v to expire, got %v", test.expected, actual)
+			}
+		})
+	}
+}
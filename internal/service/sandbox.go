@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const verifySnapshotLabel = "terraform-provider-daytona/verify-snapshot"
+
+// verifySandboxCleanupTimeout bounds the removal of the verification sandbox,
+// which also runs when the verification was cancelled.
+const verifySandboxCleanupTimeout = time.Minute
+
+// verifySnapshot starts a throwaway sandbox from the snapshot and optionally
+// runs a health-check command in it.
+func (s *Service) verifySnapshot(ctx context.Context, snapshotName, command string) (warns, errors diag.Diagnostics) {
+	createRequest := apiclient.NewCreateSandbox()
+	createRequest.SetSnapshot(snapshotName)
+	createRequest.SetLabels(map[string]string{
+		verifySnapshotLabel: snapshotName,
+	})
+
+	sandbox, err := s.API.CreateSandbox(ctx, *createRequest)
+	if err != nil {
+		errors.AddError("Snapshot Verification Error", fmt.Sprintf("Unable to create verification sandbox from snapshot %q: %v", snapshotName, err))
+		return
+	}
+
+	sandboxID := sandbox.Id
+
+	tflog.Info(ctx, "Created verification sandbox", map[string]any{
+		"sandbox_id":    sandboxID,
+		"snapshot_name": snapshotName,
+	})
+
+	// the verification sandbox is throwaway, failing to remove it shouldn't
+	// fail the snapshot creation
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), verifySandboxCleanupTimeout)
+		defer cancel()
+
+		if err := s.API.DeleteSandbox(ctx, sandboxID); err != nil {
+			warns.AddWarning("Cleanup Warning", fmt.Sprintf("Failed to delete verification sandbox %s: %v", sandboxID, err))
+		}
+	}()
+
+	errors.Append(s.waitForSandboxStarted(ctx, sandboxID)...)
+	if errors.HasError() || command == "" {
+		return
+	}
+
+	result, err := s.API.ExecuteCommand(ctx, sandboxID, command)
+	if err != nil {
+		errors.AddError("Snapshot Verification Error", fmt.Sprintf("Unable to run verification command: %v", err))
+		return
+	}
+
+	if result.ExitCode != 0 {
+		errors.AddError(
+			"Snapshot Verification Error",
+			fmt.Sprintf("Verification command exited with code %d: %s", int(result.ExitCode), result.Result),
+		)
+	}
+
+	return
+}
+
+func (s *Service) waitForSandboxStarted(ctx context.Context, sandboxID string) (errors diag.Diagnostics) {
+	ctx, cancel := s.operationContext(ctx)
+	defer cancel()
+	backoff := s.newBackoff()
+
+	for {
+		select {
+		case <-ctx.Done():
+			errors.AddError("Snapshot Verification Error", fmt.Sprintf("Cancelled while waiting for verification sandbox to start: %v", ctx.Err()))
+			return
+		default:
+		}
+
+		sandbox, err := s.API.GetSandbox(ctx, sandboxID)
+		if err != nil {
+			errors.AddError("Snapshot Verification Error", fmt.Sprintf("Unable to fetch verification sandbox: %v", err))
+			return
+		}
+
+		state := sandbox.GetState()
+		switch state {
+		case apiclient.SANDBOXSTATE_STARTED:
+			return
+		case apiclient.SANDBOXSTATE_ERROR, apiclient.SANDBOXSTATE_BUILD_FAILED:
+			errors.AddError("Snapshot Verification Error", fmt.Sprintf("Verification sandbox failed to start: %s", sandbox.GetErrorReason()))
+			return
+		}
+
+		tflog.Info(ctx, "Waiting for the verification sandbox to start", map[string]any{
+			"sandbox_id": sandboxID,
+			"state":      string(state),
+		})
+		backoff.wait(ctx)
+	}
+}
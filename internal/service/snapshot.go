@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/geldata/terraform-provider-daytona/internal/daytona"
+)
+
+// Service implements the snapshot lifecycle on top of the Daytona API and
+// the Docker daemon, independent of the Terraform resources using it.
+type Service struct {
+	API       DaytonaAPI
+	NewDocker func() (DockerAPI, error)
+	// PollInterval is the initial delay between polls of wait loops, which
+	// backs off up to MaxPollInterval
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+	// OperationTimeout bounds each wait for Daytona to finish processing,
+	// deleting or starting something. Zero means no limit.
+	OperationTimeout time.Duration
+	// HTTPClient talks to container registries when copying images between
+	// them or pushing images directly. Nil means http.DefaultClient.
+	HTTPClient *http.Client
+	// PushParallelism is how many layers direct pushes upload at a time. Zero
+	// means defaultPushParallelism.
+	PushParallelism int
+}
+
+// New creates a service talking to the Daytona API through the given
+// client and to the Docker daemon configured along with it.
+func New(client *daytona.Client) *Service {
+	return &Service{
+		API: NewDaytonaAPI(client),
+		NewDocker: func() (DockerAPI, error) {
+			return NewDockerClient(client.Docker)
+		},
+		PollInterval:     time.Second,
+		MaxPollInterval:  defaultMaxPollInterval,
+		OperationTimeout: client.OperationTimeout,
+		PushParallelism:  client.PushParallelism,
+		HTTPClient:       client.HTTPClient,
+	}
+}
+
+func (s *Service) httpClient() *http.Client {
+	if s.HTTPClient != nil {
+		return s.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// operationContext bounds a wait by the service's OperationTimeout.
+func (s *Service) operationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.OperationTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.OperationTimeout)
+}
+
+// What happens to a snapshot in Daytona when Terraform destroys it.
+const (
+	DestroyBehaviorDelete     = "delete"
+	DestroyBehaviorDeactivate = "deactivate"
+	DestroyBehaviorRetain     = "retain"
+)
+
+// SnapshotSpec is the desired configuration of a snapshot.
+type SnapshotSpec struct {
+	Name string
+	ImageSpec
+	// RemoteImageName registers the snapshot from an image already present in
+	// Daytona's registry instead of pushing ImageName
+	RemoteImageName string
+	// DockerfileContent has Daytona build the snapshot from a Dockerfile
+	// instead of registering an image
+	DockerfileContent string
+	// ContextHashes are the build contexts in Daytona's object storage that
+	// the Dockerfile can COPY and ADD files from
+	ContextHashes []string
+	// BuildContextHash identifies the files the image is built from
+	BuildContextHash string
+	// Entrypoint overrides the image's default command
+	Entrypoint     []string
+	Cpu            *int32
+	Gpu            *int32
+	Memory         *int32
+	Disk           *int32
+	VerifyOnCreate bool
+	VerifyCommand  string
+	// Async returns the snapshot once it is registered instead of waiting for
+	// it to become active, which also skips verifying it
+	Async bool
+}
+
+// RequiresRecreate reports whether moving from the snapshot described by
+// state to spec needs a new snapshot. Snapshots are immutable in Daytona, so
+// any change to their contents or resources does.
+func (spec SnapshotSpec) RequiresRecreate(state SnapshotSpec) bool {
+	// recreate if image_name changes, except when importing (state has empty image_name)
+	return (!SameImageReference(spec.ImageName, state.ImageName) && state.ImageName != "") ||
+		(spec.RemoteImageName != "" && !SameImageReference(spec.RemoteImageName, state.RemoteImageName)) ||
+		spec.DockerfileContent != state.DockerfileContent ||
+		!slices.Equal(spec.ContextHashes, state.ContextHashes) ||
+		(spec.BuildContextHash != state.BuildContextHash && state.ImageName != "") ||
+		(spec.Platform != state.Platform && state.ImageName != "") ||
+		(!slices.Equal(spec.Entrypoint, state.Entrypoint) && state.ImageName != "") ||
+		spec.Name != state.Name ||
+		!equalInt32(spec.Cpu, state.Cpu) ||
+		!equalInt32(spec.Gpu, state.Gpu) ||
+		!equalInt32(spec.Memory, state.Memory) ||
+		!equalInt32(spec.Disk, state.Disk)
+}
+
+func equalInt32(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// GetSnapshot fetches a snapshot by ID or name, returning nil if it doesn't
+// exist.
+func (s *Service) GetSnapshot(ctx context.Context, idOrName string) (snapshot *apiclient.SnapshotDto, errs diag.Diagnostics) {
+	snapshot, err := s.API.GetSnapshot(ctx, idOrName)
+	if isNotFound(err) {
+		return nil, errs
+	} else if err != nil {
+		errs.AddError("Client Error", fmt.Sprintf("Unable to read snapshot: %v", err))
+		return nil, errs
+	}
+
+	return snapshot, errs
+}
+
+// CreateSnapshot pushes the image if needed, registers the snapshot and waits
+// for it to become active, unless spec.Async is set. Snapshots built from a Dockerfile are submitted
+// to Daytona's builder without pushing anything. A leftover snapshot with the same name, e.g. from
+// an interrupted earlier attempt, is removed first. If that attempt already
+// pushed the image, it is registered without pushing it again.
+func (s *Service) CreateSnapshot(ctx context.Context, spec SnapshotSpec) (snapshot *apiclient.SnapshotDto, pushed PushedImage, warns, errs diag.Diagnostics) {
+	leftoverImage, warnings, errors := s.maybeCleanupExistingCreationAttempt(ctx, spec.Name)
+	warns.Append(warnings...)
+	errs.Append(errors...)
+	if errs.HasError() {
+		return
+	}
+
+	targetImage := spec.RemoteImageName
+
+	// a configured remote image is already in Daytona's registry and can be
+	// registered right away
+	if targetImage == "" && spec.DockerfileContent == "" {
+		var found bool
+		pushed, found = s.findPushedImage(ctx, spec.ImageSpec, leftoverImage)
+		if !found {
+			pushed, warnings, errors = s.PushImage(ctx, spec.ImageSpec)
+			warns.Append(warnings...)
+			errs.Append(errors...)
+			if errs.HasError() {
+				return
+			}
+		}
+		targetImage = pushed.RemoteImageName
+	}
+
+	snapshot, errors = s.registerSnapshot(ctx, spec, targetImage)
+	errs.Append(errors...)
+	if errs.HasError() || spec.Async {
+		return
+	}
+
+	snapshot, errors = s.ensureSnapshotAvailable(ctx, spec.Name)
+	errs.Append(errors...)
+	if errs.HasError() {
+		return
+	}
+
+	if spec.VerifyOnCreate {
+		warnings, errors = s.verifySnapshot(ctx, snapshot.Name, spec.VerifyCommand)
+		warns.Append(warnings...)
+		errs.Append(errors...)
+	}
+
+	return
+}
+
+// ReplaceSnapshot creates a new snapshot for spec, destroying the old one
+// first according to destroyBehavior. Snapshot names are unique, so an old
+// snapshot that is kept can't be replaced under the same name.
+func (s *Service) ReplaceSnapshot(ctx context.Context, oldID, oldName, destroyBehavior string, spec SnapshotSpec) (snapshot *apiclient.SnapshotDto, pushed PushedImage, warns, errs diag.Diagnostics) {
+	if destroyBehavior != DestroyBehaviorDelete && spec.Name == oldName {
+		errs.Append(ReplacementNameConflict(oldName, destroyBehavior))
+		return
+	}
+
+	errs.Append(s.DestroySnapshot(ctx, oldID, oldName, destroyBehavior)...)
+	if errs.HasError() {
+		return
+	}
+
+	return s.CreateSnapshot(ctx, spec)
+}
+
+// ReplacementNameConflict reports that a snapshot kept by its destroy
+// behavior blocks a replacement with the same name, which creating it would
+// have to delete.
+func ReplacementNameConflict(name, destroyBehavior string) diag.Diagnostic {
+	return diag.NewErrorDiagnostic(
+		"Snapshot Name Conflict",
+		fmt.Sprintf("Snapshot %q can't be replaced under the same name while destroy_behavior = %q keeps it, as snapshot names are unique. Give the replacement another name, or set destroy_behavior = \"delete\" and apply first.", name, destroyBehavior),
+	)
+}
+
+// DestroySnapshot removes a snapshot from Terraform's management. Depending
+// on behavior it is deleted, deactivated so it stays listed but can't be used
+// for new sandboxes, or retained as is.
+func (s *Service) DestroySnapshot(ctx context.Context, id, name, behavior string) (errors diag.Diagnostics) {
+	switch behavior {
+	case DestroyBehaviorRetain:
+		tflog.Info(ctx, "Keeping snapshot in Daytona due to destroy_behavior=retain", map[string]any{
+			"snapshot_id":   id,
+			"snapshot_name": name,
+		})
+		return
+	case DestroyBehaviorDeactivate:
+		tflog.Info(ctx, "Deactivating snapshot due to destroy_behavior=deactivate", map[string]any{
+			"snapshot_id":   id,
+			"snapshot_name": name,
+		})
+		err := s.API.DeactivateSnapshot(ctx, id)
+		if err != nil && !isNotFound(err) {
+			errors.AddError("Client Error", fmt.Sprintf("Unable to deactivate snapshot, got error: %v", err))
+		}
+		return
+	default:
+		return s.DeleteSnapshot(ctx, id)
+	}
+}
+
+// DeleteSnapshot removes the snapshot and waits until it is gone. A snapshot
+// that doesn't exist anymore is not an error.
+func (s *Service) DeleteSnapshot(ctx context.Context, id string) (errors diag.Diagnostics) {
+	err := s.API.RemoveSnapshot(ctx, id)
+	if isNotFound(err) {
+		return
+	} else if err != nil {
+		errors.AddError("Client Error", fmt.Sprintf("Unable to delete snapshot, got error: %v", err))
+		return
+	}
+
+	ctx, cancel := s.operationContext(ctx)
+	defer cancel()
+	backoff := s.newBackoff()
+
+	for {
+		select {
+		case <-ctx.Done():
+			errors.AddError("Deletion Error", fmt.Sprintf("Cancelled while waiting for snapshot deletion: %v", ctx.Err()))
+			return
+		default:
+			_, err := s.API.GetSnapshot(ctx, id)
+			if isNotFound(err) {
+				tflog.Info(ctx, "Snapshot successfully deleted")
+				return
+			} else if err != nil {
+				errors.AddError("Deletion Verification Error", fmt.Sprintf("Unable to verify snapshot deletion: %v", err))
+				return
+			}
+
+			tflog.Info(ctx, "Waiting for snapshot to be deleted")
+			backoff.wait(ctx)
+		}
+	}
+}
+
+// maybeCleanupExistingCreationAttempt deletes a leftover snapshot with the
+// name and returns the image it was registered with, if any.
+func (s *Service) maybeCleanupExistingCreationAttempt(ctx context.Context, snapshotName string) (leftoverImage string, warns, errors diag.Diagnostics) {
+	existingSnapshot, err := s.API.GetSnapshot(ctx, snapshotName)
+	if isNotFound(err) {
+		return
+	} else if err != nil {
+		errors.AddError("Snapshot Check", fmt.Sprintf("Unable to check for if snapshot exists: %v", err))
+		return
+	}
+	leftoverImage = existingSnapshot.GetImageName()
+
+	tflog.Info(ctx, "Found existing snapshot, deleting it", map[string]any{
+		"snapshot_id":    existingSnapshot.Id,
+		"snapshot_name":  existingSnapshot.Name,
+		"snapshot_state": string(existingSnapshot.State),
+	})
+
+	err = s.API.RemoveSnapshot(ctx, existingSnapshot.Id)
+	if err != nil {
+		warns.AddWarning("Cleanup Warning", fmt.Sprintf("Failed to delete existing failed snapshot %q: %v", snapshotName, err))
+	}
+
+	ctx, cancel := s.operationContext(ctx)
+	defer cancel()
+	backoff := s.newBackoff()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			_, err := s.API.GetSnapshot(ctx, existingSnapshot.Id)
+			if isNotFound(err) {
+				tflog.Info(ctx, "Snapshot successfully deleted")
+				return
+			}
+			backoff.wait(ctx)
+		}
+	}
+}
+
+func (s *Service) registerSnapshot(ctx context.Context, spec SnapshotSpec, targetImage string) (snapshot *apiclient.SnapshotDto, errors diag.Diagnostics) {
+	createRequest := apiclient.NewCreateSnapshot(spec.Name)
+	if spec.DockerfileContent != "" {
+		buildInfo := apiclient.NewCreateBuildInfo(spec.DockerfileContent)
+		buildInfo.ContextHashes = spec.ContextHashes
+		createRequest.SetBuildInfo(*buildInfo)
+	} else {
+		createRequest.SetImageName(targetImage)
+	}
+	createRequest.Entrypoint = spec.Entrypoint
+	createRequest.Cpu = spec.Cpu
+	createRequest.Gpu = spec.Gpu
+	createRequest.Memory = spec.Memory
+	createRequest.Disk = spec.Disk
+
+	snapshot, err := s.API.CreateSnapshot(ctx, *createRequest)
+	if err != nil {
+		errors.AddError("Client Error", fmt.Sprintf("Unable to create snapshot, got error: %v", err))
+		return
+	}
+
+	return
+}
+
+func (s *Service) ensureSnapshotAvailable(ctx context.Context, snapshotName string) (snapshot *apiclient.SnapshotDto, errs diag.Diagnostics) {
+	ctx, cancel := s.operationContext(ctx)
+	defer cancel()
+	backoff := s.newBackoff()
+
+	for {
+		select {
+		case <-ctx.Done():
+			errs.AddError("Snapshot Availability Error", fmt.Sprintf("Cancelled during waiting for snapshot to become available: %v", ctx.Err()))
+			return nil, errs
+		default:
+			var err error
+			snapshot, err = s.API.GetSnapshot(ctx, snapshotName)
+			if err != nil {
+				errs.AddError("Snapshot Availability Error", fmt.Sprintf("Unable to fetch snapshot: %v", err))
+				return
+			}
+
+			switch snapshot.State {
+			case apiclient.SNAPSHOTSTATE_ACTIVE:
+				return
+			case apiclient.SNAPSHOTSTATE_ERROR, apiclient.SNAPSHOTSTATE_BUILD_FAILED:
+				reason := "unknown reason"
+				if snapshot.ErrorReason.IsSet() {
+					reason = *snapshot.ErrorReason.Get()
+				}
+				if snapshot.State == apiclient.SNAPSHOTSTATE_BUILD_FAILED {
+					reason += s.buildLogsTail(ctx, snapshot.Id)
+				}
+				errs.AddError("Snapshot Availability Error", fmt.Sprintf("Snapshot processing failed: %s", reason))
+				return
+			}
+		}
+
+		tflog.Info(ctx, "Waiting for the snapshot to be processed", map[string]any{
+			"state": string(snapshot.State),
+		})
+		backoff.wait(ctx)
+	}
+}
+
+// buildLogsTail returns the last lines of a failed build's logs, which
+// usually explain the failure, or nothing if they can't be fetched.
+func (s *Service) buildLogsTail(ctx context.Context, snapshotID string) string {
+	logs, err := s.API.GetSnapshotBuildLogs(ctx, snapshotID)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to fetch build logs", map[string]any{
+			"snapshot_id": snapshotID,
+			"error":       err.Error(),
+		})
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimRight(logs, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return ""
+	}
+	return "\n" + strings.Join(lines[max(0, len(lines)-buildOutputLines):], "\n")
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
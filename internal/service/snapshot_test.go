@@ -0,0 +1,569 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/apiclient"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func int32Pointer(v int32) *int32 {
+	return &v
+}
+
+func requireNoErrors(t *testing.T, diags diag.Diagnostics) {
+	t.Helper()
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags.Errors())
+	}
+}
+
+func requireError(t *testing.T, diags diag.Diagnostics, substring string) {
+	t.Helper()
+	for _, d := range diags.Errors() {
+		if strings.Contains(d.Detail(), substring) {
+			return
+		}
+	}
+	t.Fatalf("expected an error containing %q, got: %v", substring, diags.Errors())
+}
+
+func TestCreateSnapshotPushesLocalImage(t *testing.T) {
+	api := newFakeAPI()
+	docker := newFakeDocker("app:latest")
+	s := newTestService(api, docker)
+
+	snapshot, _, _, errs := s.CreateSnapshot(context.Background(), SnapshotSpec{
+		Name:       "app",
+		ImageSpec:  ImageSpec{ImageName: "app:latest", ImageSources: []string{ImageSourceLocal}},
+		Entrypoint: []string{"sleep", "infinity"},
+		Cpu:        int32Pointer(2),
+		Gpu:        int32Pointer(1),
+	})
+	requireNoErrors(t, errs)
+
+	if snapshot.State != apiclient.SNAPSHOTSTATE_ACTIVE {
+		t.Errorf("expected an active snapshot, got state %q", snapshot.State)
+	}
+	if snapshot.Cpu != 2 {
+		t.Errorf("expected 2 CPUs, got %v", snapshot.Cpu)
+	}
+	if snapshot.Gpu != 1 {
+		t.Errorf("expected 1 GPU, got %v", snapshot.Gpu)
+	}
+	if !slices.Equal(snapshot.Entrypoint, []string{"sleep", "infinity"}) {
+		t.Errorf("unexpected entrypoint %v", snapshot.Entrypoint)
+	}
+
+	remote := snapshot.GetImageName()
+	if !strings.HasPrefix(remote, "registry.example.com/project/app:") {
+		t.Errorf("unexpected remote image name %q", remote)
+	}
+	if !slices.Contains(api.calls, "CreateSnapshot app "+remote) {
+		t.Errorf("snapshot wasn't registered from the pushed image, calls: %v", api.calls)
+	}
+	if !slices.Contains(docker.calls, "ImagePush "+remote) {
+		t.Errorf("image wasn't pushed, calls: %v", docker.calls)
+	}
+	if docker.images[remote] {
+		t.Errorf("remote tag %q was left behind", remote)
+	}
+}
+
+func TestCreateSnapshotFromRemoteImage(t *testing.T) {
+	api := newFakeAPI()
+	docker := newFakeDocker()
+	s := newTestService(api, docker)
+
+	snapshot, _, _, errs := s.CreateSnapshot(context.Background(), SnapshotSpec{
+		Name:            "app",
+		RemoteImageName: "registry.example.com/project/app:1",
+	})
+	requireNoErrors(t, errs)
+
+	if snapshot.GetImageName() != "registry.example.com/project/app:1" {
+		t.Errorf("unexpected remote image name %q", snapshot.GetImageName())
+	}
+	if len(docker.calls) != 0 {
+		t.Errorf("docker shouldn't be used for remote images, calls: %v", docker.calls)
+	}
+}
+
+func TestCreateSnapshotAsync(t *testing.T) {
+	api := newFakeAPI()
+	s := newTestService(api, newFakeDocker())
+
+	snapshot, _, _, errs := s.CreateSnapshot(context.Background(), SnapshotSpec{
+		Name:            "app",
+		RemoteImageName: "registry.example.com/project/app:1",
+		Async:           true,
+	})
+	requireNoErrors(t, errs)
+
+	if snapshot.State != apiclient.SNAPSHOTSTATE_PENDING {
+		t.Errorf("expected the registered, pending snapshot, got state %q", snapshot.State)
+	}
+	if api.pending[snapshot.Id] != 2 {
+		t.Errorf("snapshot shouldn't be polled, %d polls left", api.pending[snapshot.Id])
+	}
+}
+
+func TestCreateSnapshotFromDockerfile(t *testing.T) {
+	api := newFakeAPI()
+	docker := newFakeDocker()
+	s := newTestService(api, docker)
+
+	_, _, _, errs := s.CreateSnapshot(context.Background(), SnapshotSpec{
+		Name:              "app",
+		DockerfileContent: "FROM ubuntu",
+		ContextHashes:     []string{"abc", "def"},
+	})
+	requireNoErrors(t, errs)
+
+	if !slices.Contains(api.calls, "CreateSnapshot app build FROM ubuntu abc,def") {
+		t.Errorf("snapshot wasn't submitted for building, calls: %v", api.calls)
+	}
+	if len(docker.calls) != 0 {
+		t.Errorf("docker shouldn't be used for builds, calls: %v", docker.calls)
+	}
+}
+
+func TestCreateSnapshotCleansUpPreviousAttempt(t *testing.T) {
+	api := newFakeAPI()
+	api.addSnapshot("stale", "app", apiclient.SNAPSHOTSTATE_ERROR)
+	api.removalDelay = 2
+	s := newTestService(api, newFakeDocker())
+
+	_, _, _, errs := s.CreateSnapshot(context.Background(), SnapshotSpec{
+		Name:            "app",
+		RemoteImageName: "registry.example.com/project/app:1",
+	})
+	requireNoErrors(t, errs)
+
+	removed := slices.Index(api.calls, "RemoveSnapshot stale")
+	created := slices.Index(api.calls, "CreateSnapshot app registry.example.com/project/app:1")
+	if removed == -1 || created == -1 || removed > created {
+		t.Errorf("stale snapshot wasn't removed before creating the new one, calls: %v", api.calls)
+	}
+	if _, ok := api.snapshots["stale"]; ok {
+		t.Errorf("stale snapshot still exists")
+	}
+}
+
+func TestCreateSnapshotBuildFailure(t *testing.T) {
+	api := newFakeAPI()
+	api.snapshotFinalState = apiclient.SNAPSHOTSTATE_BUILD_FAILED
+	s := newTestService(api, newFakeDocker())
+
+	_, _, _, errs := s.CreateSnapshot(context.Background(), SnapshotSpec{
+		Name:            "app",
+		RemoteImageName: "registry.example.com/project/app:1",
+	})
+	requireError(t, errs, "build exploded")
+}
+
+func TestCreateSnapshotBuildFailureIncludesLogs(t *testing.T) {
+	api := newFakeAPI()
+	api.snapshotFinalState = apiclient.SNAPSHOTSTATE_BUILD_FAILED
+	api.buildLogs = "Step 1/2 : FROM ubuntu\nStep 2/2 : RUN false\nreturned a non-zero code: 1\n"
+	s := newTestService(api, newFakeDocker())
+
+	_, _, _, errs := s.CreateSnapshot(context.Background(), SnapshotSpec{
+		Name:              "app",
+		DockerfileContent: "FROM ubuntu\nRUN false",
+	})
+	requireError(t, errs, "build exploded\nStep 1/2 : FROM ubuntu\nStep 2/2 : RUN false\nreturned a non-zero code: 1")
+}
+
+func TestCreateSnapshotReturnsPushedImageOnFailure(t *testing.T) {
+	api := newFakeAPI()
+	api.snapshotFinalState = apiclient.SNAPSHOTSTATE_BUILD_FAILED
+	s := newTestService(api, newFakeDocker("app:latest"))
+
+	_, pushed, _, errs := s.CreateSnapshot(context.Background(), SnapshotSpec{
+		Name:      "app",
+		ImageSpec: ImageSpec{ImageName: "app:latest", ImageSources: []string{ImageSourceLocal}},
+	})
+	requireError(t, errs, "build exploded")
+
+	if !strings.HasPrefix(pushed.RemoteImageName, "registry.example.com/project/app:") {
+		t.Errorf("unexpected remote image name %q", pushed.RemoteImageName)
+	}
+	if pushed.LocalImageID != "app:latest" {
+		t.Errorf("unexpected local image ID %q", pushed.LocalImageID)
+	}
+}
+
+func TestCreateSnapshotReusesPushedImage(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "app.tar")
+	os.WriteFile(archivePath, writeImageArchive(t, "app:1.0", "linux/amd64", layerTar(t, "app", "binary")), 0o644)
+
+	tests := map[string]struct {
+		remoteTag string
+		// registered reports whether the failed attempt registered a snapshot
+		registered bool
+	}{
+		"digest tag, registration failed":    {remoteTag: "{tag}-{digest}"},
+		"digest tag, snapshot failed":        {remoteTag: "{tag}-{digest}", registered: true},
+		"timestamp tag, registration failed": {},
+		"timestamp tag, snapshot failed":     {registered: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			registry := newFakeRegistry(t)
+			s := newDirectPushService(t, registry, nil)
+			api := s.API.(*fakeAPI)
+			spec := SnapshotSpec{
+				Name: "app",
+				ImageSpec: ImageSpec{
+					ImageName:    "app:1.0",
+					ImageSources: []string{ImageSourceArchive},
+					ImageArchive: archivePath,
+					RemoteTag:    test.remoteTag,
+					PushMode:     PushModeDirect,
+				},
+			}
+
+			api.snapshotFinalState = apiclient.SNAPSHOTSTATE_BUILD_FAILED
+			_, failed, _, errs := s.CreateSnapshot(context.Background(), spec)
+			requireError(t, errs, "build exploded")
+			if !test.registered {
+				delete(api.snapshots, "snapshot-app")
+			}
+
+			// pushing again would fail, pulling still works
+			registry.setCredentials("user", "rotated", false)
+
+			api.snapshotFinalState = apiclient.SNAPSHOTSTATE_ACTIVE
+			snapshot, pushed, _, errs := s.CreateSnapshot(context.Background(), spec)
+			requireNoErrors(t, errs)
+
+			if pushed != failed {
+				t.Errorf("expected the image pushed by the failed attempt %+v, got %+v", failed, pushed)
+			}
+			if snapshot.GetImageName() != failed.RemoteImageName {
+				t.Errorf("expected the snapshot to be registered from %q, got %q", failed.RemoteImageName, snapshot.GetImageName())
+			}
+		})
+	}
+}
+
+func TestCreateSnapshotPushesChangedImageAgain(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "app.tar")
+	os.WriteFile(archivePath, writeImageArchive(t, "app:1.0", "linux/amd64", layerTar(t, "app", "binary")), 0o644)
+
+	registry := newFakeRegistry(t)
+	s := newDirectPushService(t, registry, nil)
+	api := s.API.(*fakeAPI)
+	spec := SnapshotSpec{
+		Name: "app",
+		ImageSpec: ImageSpec{
+			ImageName:    "app:1.0",
+			ImageSources: []string{ImageSourceArchive},
+			ImageArchive: archivePath,
+			RemoteTag:    "{tag}-{digest}",
+			PushMode:     PushModeDirect,
+		},
+	}
+
+	api.snapshotFinalState = apiclient.SNAPSHOTSTATE_BUILD_FAILED
+	_, _, _, errs := s.CreateSnapshot(context.Background(), spec)
+	requireError(t, errs, "build exploded")
+
+	// the image was rebuilt since, so it has to be pushed again
+	os.WriteFile(archivePath, writeImageArchive(t, "app:1.0", "linux/amd64", layerTar(t, "app", "fixed binary")), 0o644)
+	registry.setCredentials("user", "rotated", false)
+
+	api.snapshotFinalState = apiclient.SNAPSHOTSTATE_ACTIVE
+	_, _, _, errs = s.CreateSnapshot(context.Background(), spec)
+	requireError(t, errs, "401 Unauthorized")
+}
+
+func TestCreateSnapshotImageNotFound(t *testing.T) {
+	api := newFakeAPI()
+	s := newTestService(api, newFakeDocker())
+
+	_, _, _, errs := s.CreateSnapshot(context.Background(), SnapshotSpec{
+		Name:      "app",
+		ImageSpec: ImageSpec{ImageName: "app:latest", ImageSources: []string{ImageSourceLocal, ImageSourceRegistry}},
+	})
+	requireError(t, errs, "pull access denied")
+
+	if len(api.calls) != 0 {
+		t.Errorf("nothing should be registered without an image, calls: %v", api.calls)
+	}
+}
+
+func TestCreateSnapshotVerification(t *testing.T) {
+	tests := map[string]struct {
+		sandboxState apiclient.SandboxState
+		command      string
+		exitCode     float32
+		expectError  string
+	}{
+		"started": {
+			sandboxState: apiclient.SANDBOXSTATE_STARTED,
+		},
+		"command succeeds": {
+			sandboxState: apiclient.SANDBOXSTATE_STARTED,
+			command:      "true",
+		},
+		"command fails": {
+			sandboxState: apiclient.SANDBOXSTATE_STARTED,
+			command:      "false",
+			exitCode:     1,
+			expectError:  "exited with code 1",
+		},
+		"sandbox fails to start": {
+			sandboxState: apiclient.SANDBOXSTATE_ERROR,
+			command:      "true",
+			expectError:  "no capacity",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := newFakeAPI()
+			api.sandboxFinalState = test.sandboxState
+			api.exitCode = test.exitCode
+			s := newTestService(api, newFakeDocker())
+
+			_, _, _, errs := s.CreateSnapshot(context.Background(), SnapshotSpec{
+				Name:            "app",
+				RemoteImageName: "registry.example.com/project/app:1",
+				VerifyOnCreate:  true,
+				VerifyCommand:   test.command,
+			})
+
+			if test.expectError == "" {
+				requireNoErrors(t, errs)
+			} else {
+				requireError(t, errs, test.expectError)
+			}
+
+			ran := slices.Contains(api.calls, "ExecuteCommand sandbox-app "+test.command)
+			if ran != (test.command != "" && test.sandboxState == apiclient.SANDBOXSTATE_STARTED) {
+				t.Errorf("unexpected command execution, calls: %v", api.calls)
+			}
+			if len(api.sandboxes) != 0 {
+				t.Errorf("verification sandbox wasn't deleted")
+			}
+		})
+	}
+}
+
+func TestVerifySnapshotCancelled(t *testing.T) {
+	api := newFakeAPI()
+	// the sandbox never starts, so the verification runs until it's cancelled
+	api.sandboxFinalState = apiclient.SANDBOXSTATE_CREATING
+	s := newTestService(api, newFakeDocker())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	warns, errs := s.verifySnapshot(ctx, "app", "")
+	requireError(t, errs, "Cancelled while waiting")
+	if len(warns) != 0 || len(api.sandboxes) != 0 {
+		t.Errorf("verification sandbox wasn't deleted, warnings: %v", warns)
+	}
+}
+
+func TestReplaceSnapshot(t *testing.T) {
+	for behavior, expected := range map[string]apiclient.SnapshotState{
+		DestroyBehaviorDelete:     "",
+		DestroyBehaviorDeactivate: apiclient.SNAPSHOTSTATE_INACTIVE,
+		DestroyBehaviorRetain:     apiclient.SNAPSHOTSTATE_ACTIVE,
+	} {
+		api := newFakeAPI()
+		api.addSnapshot("old", "old-app", apiclient.SNAPSHOTSTATE_ACTIVE)
+		api.removalDelay = 1
+		s := newTestService(api, newFakeDocker())
+
+		snapshot, _, _, errs := s.ReplaceSnapshot(context.Background(), "old", "old-app", behavior, SnapshotSpec{
+			Name:            "app",
+			RemoteImageName: "registry.example.com/project/app:1",
+		})
+		requireNoErrors(t, errs)
+
+		if snapshot.Name != "app" {
+			t.Errorf("unexpected snapshot %q", snapshot.Name)
+		}
+
+		var state apiclient.SnapshotState
+		if old, kept := api.snapshots["old"]; kept {
+			state = old.State
+		}
+		if state != expected {
+			t.Errorf("destroy_behavior=%s left the old snapshot in state %q, expected %q", behavior, state, expected)
+		}
+	}
+}
+
+func TestReplaceSnapshotKeptUnderSameName(t *testing.T) {
+	for _, behavior := range []string{DestroyBehaviorRetain, DestroyBehaviorDeactivate} {
+		api := newFakeAPI()
+		api.addSnapshot("old", "app", apiclient.SNAPSHOTSTATE_ACTIVE)
+		s := newTestService(api, newFakeDocker())
+
+		_, _, _, errs := s.ReplaceSnapshot(context.Background(), "old", "app", behavior, SnapshotSpec{
+			Name:            "app",
+			RemoteImageName: "registry.example.com/project/app:2",
+		})
+		requireError(t, errs, "can't be replaced under the same name")
+
+		if old, kept := api.snapshots["old"]; !kept || old.State != apiclient.SNAPSHOTSTATE_ACTIVE {
+			t.Errorf("destroy_behavior=%s didn't leave the old snapshot untouched", behavior)
+		}
+		if len(api.calls) != 0 {
+			t.Errorf("destroy_behavior=%s shouldn't destroy or create anything, calls: %v", behavior, api.calls)
+		}
+	}
+}
+
+func TestDestroySnapshotDeactivateMissing(t *testing.T) {
+	s := newTestService(newFakeAPI(), newFakeDocker())
+
+	requireNoErrors(t, s.DestroySnapshot(context.Background(), "app", "app", DestroyBehaviorDeactivate))
+}
+
+func TestDeleteSnapshot(t *testing.T) {
+	api := newFakeAPI()
+	api.addSnapshot("app", "app", apiclient.SNAPSHOTSTATE_ACTIVE)
+	api.removalDelay = 3
+	s := newTestService(api, newFakeDocker())
+
+	requireNoErrors(t, s.DeleteSnapshot(context.Background(), "app"))
+	if _, ok := api.snapshots["app"]; ok {
+		t.Errorf("deletion returned before the snapshot was gone")
+	}
+
+	// deleting again is a no-op
+	requireNoErrors(t, s.DeleteSnapshot(context.Background(), "app"))
+}
+
+func TestDeleteSnapshotCancelled(t *testing.T) {
+	api := newFakeAPI()
+	api.addSnapshot("app", "app", apiclient.SNAPSHOTSTATE_ACTIVE)
+	api.removalDelay = 1 << 30
+	s := newTestService(api, newFakeDocker())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	requireError(t, s.DeleteSnapshot(ctx, "app"), "Cancelled")
+}
+
+func TestDeleteSnapshotOperationTimeout(t *testing.T) {
+	api := newFakeAPI()
+	api.addSnapshot("app", "app", apiclient.SNAPSHOTSTATE_ACTIVE)
+	api.removalDelay = 1 << 30
+	s := newTestService(api, newFakeDocker())
+	s.OperationTimeout = 10 * time.Millisecond
+
+	requireError(t, s.DeleteSnapshot(context.Background(), "app"), "deadline exceeded")
+}
+
+func TestGetSnapshotNotFound(t *testing.T) {
+	s := newTestService(newFakeAPI(), newFakeDocker())
+
+	snapshot, errs := s.GetSnapshot(context.Background(), "missing")
+	requireNoErrors(t, errs)
+	if snapshot != nil {
+		t.Errorf("expected no snapshot, got %v", snapshot)
+	}
+}
+
+func TestRequiresRecreate(t *testing.T) {
+	state := SnapshotSpec{
+		Name:            "app",
+		ImageSpec:       ImageSpec{ImageName: "app:1"},
+		RemoteImageName: "registry.example.com/project/app:1",
+		Cpu:             int32Pointer(1),
+		Gpu:             int32Pointer(0),
+		Memory:          int32Pointer(1),
+		Disk:            int32Pointer(3),
+	}
+
+	tests := map[string]struct {
+		modify   func(spec *SnapshotSpec)
+		state    func(state *SnapshotSpec)
+		expected bool
+	}{
+		"unchanged": {
+			modify: func(spec *SnapshotSpec) {},
+		},
+		"verification settings only": {
+			modify: func(spec *SnapshotSpec) {
+				spec.VerifyOnCreate = true
+				spec.VerifyCommand = "true"
+			},
+		},
+		"remote image still unknown": {
+			modify: func(spec *SnapshotSpec) { spec.RemoteImageName = "" },
+		},
+		"image name": {
+			modify:   func(spec *SnapshotSpec) { spec.ImageName = "app:2" },
+			expected: true,
+		},
+		"equivalent image name": {
+			modify: func(spec *SnapshotSpec) { spec.ImageName = "docker.io/library/app:1" },
+		},
+		"image name after import": {
+			modify: func(spec *SnapshotSpec) { spec.ImageName = "app:2" },
+			state:  func(state *SnapshotSpec) { state.ImageName = "" },
+		},
+		"remote image name": {
+			modify: func(spec *SnapshotSpec) {
+				spec.ImageName = ""
+				spec.RemoteImageName = "registry.example.com/project/app:2"
+			},
+			state:    func(state *SnapshotSpec) { state.ImageName = "" },
+			expected: true,
+		},
+		"entrypoint": {
+			modify:   func(spec *SnapshotSpec) { spec.Entrypoint = []string{"sleep", "infinity"} },
+			expected: true,
+		},
+		"entrypoint after import": {
+			modify: func(spec *SnapshotSpec) { spec.Entrypoint = []string{"sleep", "infinity"} },
+			state:  func(state *SnapshotSpec) { state.ImageName = "" },
+		},
+		"name": {
+			modify:   func(spec *SnapshotSpec) { spec.Name = "other" },
+			expected: true,
+		},
+		"cpu": {
+			modify:   func(spec *SnapshotSpec) { spec.Cpu = int32Pointer(2) },
+			expected: true,
+		},
+		"gpu": {
+			modify:   func(spec *SnapshotSpec) { spec.Gpu = int32Pointer(1) },
+			expected: true,
+		},
+		"disk": {
+			modify:   func(spec *SnapshotSpec) { spec.Disk = nil },
+			expected: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec, current := state, state
+			test.modify(&spec)
+			if test.state != nil {
+				test.state(&current)
+			}
+
+			if actual := spec.RequiresRecreate(current); actual != test.expected {
+				t.Errorf("expected RequiresRecreate to be %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distribution/reference"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = imageReferenceValidator{}
+
+type imageReferenceValidator struct {
+	allowEmpty bool
+}
+
+// ImageReference checks that a string is a container image reference the
+// Docker CLI would accept, like ubuntu:22.04 or
+// ghcr.io/org/app@sha256:<digest>.
+func ImageReference() validator.String {
+	return imageReferenceValidator{}
+}
+
+// ImageReferenceOrEmpty is ImageReference but also accepts an empty string,
+// like the image_name of imported snapshots.
+func ImageReferenceOrEmpty() validator.String {
+	return imageReferenceValidator{allowEmpty: true}
+}
+
+func (v imageReferenceValidator) Description(ctx context.Context) string {
+	return "value must be a container image reference, e.g. ubuntu:22.04 or ghcr.io/org/app:1.0"
+}
+
+func (v imageReferenceValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v imageReferenceValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if v.allowEmpty && req.ConfigValue.ValueString() == "" {
+		return
+	}
+
+	if _, err := reference.ParseNormalizedNamed(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Value %q is not allowed, %s: %v", req.ConfigValue.ValueString(), v.Description(ctx), err),
+		)
+	}
+}
@@ -0,0 +1,45 @@
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// validateString reports whether the validator accepts the value.
+func validateString(v validator.String, value types.String) bool {
+	resp := &validator.StringResponse{}
+	v.ValidateString(context.Background(), validator.StringRequest{Path: path.Root("attribute"), ConfigValue: value}, resp)
+	return !resp.Diagnostics.HasError()
+}
+
+func TestImageReference(t *testing.T) {
+	tests := map[string]struct {
+		value        types.String
+		valid        bool
+		validOrEmpty bool
+	}{
+		"name":      {value: types.StringValue("ubuntu"), valid: true, validOrEmpty: true},
+		"tagged":    {value: types.StringValue("ghcr.io/org/app:1.0"), valid: true, validOrEmpty: true},
+		"pinned":    {value: types.StringValue("ubuntu@sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"), valid: true, validOrEmpty: true},
+		"uppercase": {value: types.StringValue("Ubuntu:22.04")},
+		"invalid":   {value: types.StringValue("app:not a tag")},
+		"empty":     {value: types.StringValue(""), validOrEmpty: true},
+		"null":      {value: types.StringNull(), valid: true, validOrEmpty: true},
+		"unknown":   {value: types.StringUnknown(), valid: true, validOrEmpty: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if valid := validateString(ImageReference(), test.value); valid != test.valid {
+				t.Errorf("expected ImageReference to accept %s: %t, got %t", test.value, test.valid, valid)
+			}
+			if valid := validateString(ImageReferenceOrEmpty(), test.value); valid != test.validOrEmpty {
+				t.Errorf("expected ImageReferenceOrEmpty to accept %s: %t, got %t", test.value, test.validOrEmpty, valid)
+			}
+		})
+	}
+}
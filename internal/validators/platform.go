@@ -0,0 +1,43 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = platformValidator{}
+
+var platformRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+(/[A-Za-z0-9_.-]+)?$`)
+
+type platformValidator struct{}
+
+// Platform checks that a string is an os/arch[/variant] platform like
+// linux/amd64.
+func Platform() validator.String {
+	return platformValidator{}
+}
+
+func (v platformValidator) Description(ctx context.Context) string {
+	return "value must have the form os/arch[/variant], e.g. linux/amd64"
+}
+
+func (v platformValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v platformValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !platformRegexp.MatchString(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Value %q is not allowed, %s", req.ConfigValue.ValueString(), v.Description(ctx)),
+		)
+	}
+}
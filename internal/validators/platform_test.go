@@ -0,0 +1,29 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPlatform(t *testing.T) {
+	tests := map[string]struct {
+		value types.String
+		valid bool
+	}{
+		"os and arch":  {value: types.StringValue("linux/amd64"), valid: true},
+		"with variant": {value: types.StringValue("linux/arm64/v8"), valid: true},
+		"arch only":    {value: types.StringValue("amd64")},
+		"too long":     {value: types.StringValue("linux/arm64/v8/extra")},
+		"empty part":   {value: types.StringValue("linux/")},
+		"null":         {value: types.StringNull(), valid: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if valid := validateString(Platform(), test.value); valid != test.valid {
+				t.Errorf("expected %s to be valid: %t, got %t", test.value, test.valid, valid)
+			}
+		})
+	}
+}
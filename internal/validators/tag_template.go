@@ -0,0 +1,60 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = tagTemplateValidator{}
+
+var (
+	placeholderRegexp = regexp.MustCompile(`\{[^{}]*\}`)
+	tagRegexp         = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+)
+
+type tagTemplateValidator struct {
+	placeholders []string
+}
+
+// TagTemplate checks that a string is an image tag, which may contain the
+// given placeholders like {timestamp}.
+func TagTemplate(placeholders ...string) validator.String {
+	return tagTemplateValidator{placeholders: placeholders}
+}
+
+func (v tagTemplateValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be an image tag of letters, digits, underscores, periods and dashes, optionally using the placeholders %s", strings.Join(v.placeholders, ", "))
+}
+
+func (v tagTemplateValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v tagTemplateValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	valid := true
+	// placeholders expand to valid tag characters
+	expanded := placeholderRegexp.ReplaceAllStringFunc(value, func(placeholder string) string {
+		if !slices.Contains(v.placeholders, placeholder) {
+			valid = false
+		}
+		return "x"
+	})
+
+	if !valid || !tagRegexp.MatchString(expanded) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Value %q is not allowed, %s", value, v.Description(ctx)),
+		)
+	}
+}
@@ -0,0 +1,34 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestTagTemplate(t *testing.T) {
+	tests := map[string]struct {
+		value types.String
+		valid bool
+	}{
+		"tag":                   {value: types.StringValue("v1.0"), valid: true},
+		"placeholders":          {value: types.StringValue("{tag}-{digest}"), valid: true},
+		"only a placeholder":    {value: types.StringValue("{timestamp}"), valid: true},
+		"unknown placeholder":   {value: types.StringValue("{sha}")},
+		"unclosed placeholder":  {value: types.StringValue("{tag")},
+		"leading period":        {value: types.StringValue(".{tag}")},
+		"invalid character":     {value: types.StringValue("v1:0")},
+		"too long":              {value: types.StringValue(strings.Repeat("a", 129))},
+		"long with placeholder": {value: types.StringValue(strings.Repeat("a", 127) + "{tag}"), valid: true},
+		"null":                  {value: types.StringNull(), valid: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if valid := validateString(TagTemplate("{timestamp}", "{tag}", "{digest}"), test.value); valid != test.valid {
+				t.Errorf("expected %s to be valid: %t, got %t", test.value, test.valid, valid)
+			}
+		})
+	}
+}